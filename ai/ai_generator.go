@@ -4,31 +4,40 @@ import (
     "context"
     "fmt"
     "strings"
+    "time"
 
-    "github.com/sashabaranov/go-openai"
+    "github.com/C9b3rD3vi1/jobhunter-tool/certifications"
     "github.com/C9b3rD3vi1/jobhunter-tool/models"
+    "github.com/C9b3rD3vi1/jobhunter-tool/profile"
+    "github.com/C9b3rD3vi1/jobhunter-tool/sections"
 )
 
 type AIGenerator struct {
-    client *openai.Client
+    provider LLMProvider
+    prompts  *Prompts
 }
 
-func NewAIGenerator(apiKey string) *AIGenerator {
-    if apiKey == "" {
-        return &AIGenerator{client: nil}
-    }
-    return &AIGenerator{
-        client: openai.NewClient(apiKey),
-    }
+// NewAIGenerator generates text with the given provider and the built-in
+// prompts. With a nil provider, cover letters fall back to a template.
+func NewAIGenerator(provider LLMProvider) *AIGenerator {
+    return &AIGenerator{provider: provider, prompts: NewPrompts("")}
 }
 
-func (g *AIGenerator) GenerateSkillsAnalysis(jobDescription string, userSkills []string) models.SkillsAnalysis {
+// GenerateSkillsAnalysis compares a job's requirements with the user's
+// skills and certifications. Skills the user has not listed but that their
+// profile shows they have used count as matches, with a nudge to list them.
+func (g *AIGenerator) GenerateSkillsAnalysis(jobDescription string, userSkills []string, userCerts []models.UserCertification, user *models.UserProfile) models.SkillsAnalysis {
     analysis := models.SkillsAnalysis{
-        MissingSkills:   []string{},
-        MatchingSkills:  []string{},
-        Transferable:    []string{},
-        Recommendations: []string{},
+        MissingSkills:    []string{},
+        MatchingSkills:   []string{},
+        Transferable:     []string{},
+        Recommendations:  []string{},
+        NiceToHaveSkills: []string{},
     }
+
+    // Certifications are matched separately from skills, taking expiry into account
+    analysis.Certifications = certifications.MatchRequirements(
+        certifications.Extract(jobDescription), userCerts, time.Now())
     
     descLower := strings.ToLower(jobDescription)
     userSkillsLower := make([]string, len(userSkills))
@@ -38,30 +47,70 @@ func (g *AIGenerator) GenerateSkillsAnalysis(jobDescription string, userSkills [
 
     // Extract required skills from job description
     requiredSkills := g.extractRequiredSkills(descLower)
-    
+
+    var profileLower string
+    if user != nil {
+        profileLower = strings.ToLower(profile.Text(user))
+    }
+    var unlisted []string
+
+    // Weigh each skill by the section it appears in, so a gap in the
+    // requirements hurts the fit score more than a missing nice-to-have
+    parsed := sections.Parse(jobDescription)
+    var totalWeight, matchedWeight float64
+    var missingNiceToHave []string
+
     // Find matches and gaps
     for _, reqSkill := range requiredSkills {
+        weight := sections.TermWeight(parsed, reqSkill)
+        if weight == 0 {
+            weight = sections.Weight(sections.Overview)
+        }
+        niceToHave := weight <= sections.Weight(sections.NiceToHave)
+        if niceToHave {
+            analysis.NiceToHaveSkills = append(analysis.NiceToHaveSkills, reqSkill)
+        }
+        totalWeight += weight
+
         found := false
         for _, userSkill := range userSkillsLower {
-            if strings.Contains(userSkill, reqSkill) || strings.Contains(reqSkill, userSkill) {
+            if sections.Mentions(userSkill, reqSkill) || sections.Mentions(reqSkill, userSkill) {
                 analysis.MatchingSkills = append(analysis.MatchingSkills, reqSkill)
+                matchedWeight += weight
                 found = true
                 break
             }
         }
+        if !found && profileLower != "" && sections.Mentions(profileLower, reqSkill) {
+            analysis.MatchingSkills = append(analysis.MatchingSkills, reqSkill)
+            matchedWeight += weight
+            unlisted = append(unlisted, reqSkill)
+            found = true
+        }
         if !found {
-            analysis.MissingSkills = append(analysis.MissingSkills, reqSkill)
+            if niceToHave {
+                missingNiceToHave = append(missingNiceToHave, reqSkill)
+            } else {
+                analysis.MissingSkills = append(analysis.MissingSkills, reqSkill)
+            }
         }
     }
+    // Required gaps first, so recommendations prioritize them
+    analysis.MissingSkills = append(analysis.MissingSkills, missingNiceToHave...)
 
     // Calculate fit score
-    if len(requiredSkills) > 0 {
-        analysis.FitScore = (len(analysis.MatchingSkills) * 100) / len(requiredSkills)
+    if totalWeight > 0 {
+        analysis.FitScore = int(matchedWeight * 100 / totalWeight)
     }
 
     // Generate transferable skills and recommendations
     analysis.Transferable = g.generateTransferableSkills(analysis.MissingSkills, analysis.MatchingSkills)
     analysis.Recommendations = g.generateRecommendations(analysis.MissingSkills, analysis.MatchingSkills, analysis.FitScore)
+    analysis.Recommendations = append(analysis.Recommendations, g.generateCertificationRecommendations(analysis.Certifications)...)
+    if len(unlisted) > 0 {
+        analysis.Recommendations = append(analysis.Recommendations,
+            fmt.Sprintf("Your profile shows experience with %s; add them to your skills", strings.Join(unlisted, ", ")))
+    }
 
     return analysis
 }
@@ -74,7 +123,7 @@ func (g *AIGenerator) extractRequiredSkills(description string) []string {
         "docker", "kubernetes", "terraform", "ansible", "jenkins", "git",
         "fortinet", "palo alto", "cisco", "check point", "siem", "splunk",
         "qradar", "arcsight", "wireshark", "metasploit", "nessus", "nexpose",
-        "burp suite", "nmap", "security+", "ceh", "cissp", "oscp", "gsoc",
+        "burp suite", "nmap",
         "firewall", "vpn", "ids", "ips", "dlp", "soc", "incident response",
         "threat intelligence", "vulnerability management", "penetration testing",
         "risk assessment", "compliance", "iso 27001", "nist", "pci dss",
@@ -82,7 +131,7 @@ func (g *AIGenerator) extractRequiredSkills(description string) []string {
     }
     
     for _, skill := range skillKeywords {
-        if strings.Contains(description, skill) {
+        if sections.Mentions(description, skill) {
             // Capitalize skill name
             capitalized := strings.Title(skill)
             // Avoid duplicates
@@ -158,55 +207,122 @@ func (g *AIGenerator) generateRecommendations(missingSkills, matchingSkills []st
     return recommendations
 }
 
-func (g *AIGenerator) GenerateCoverLetter(jobTitle, company, jobDescription, userProfile string) (string, error) {
-    if g.client == nil {
-        return g.generateFallbackCoverLetter(jobTitle, company, jobDescription), nil
+func (g *AIGenerator) generateCertificationRecommendations(certs []models.CertificationMatch) []string {
+    recommendations := []string{}
+
+    var unmet, expiring []string
+    for _, cert := range certs {
+        switch cert.Status {
+        case certifications.StatusUnmet:
+            unmet = append(unmet, cert.Name)
+        case certifications.StatusExpiring:
+            expiring = append(expiring, cert.Name)
+        }
     }
 
-    prompt := fmt.Sprintf(`
-Generate a professional cover letter for a cybersecurity position with the following details:
+    if len(unmet) > 0 {
+        recommendations = append(recommendations,
+            fmt.Sprintf("Certifications requested that you don't hold: %s", strings.Join(unmet, ", ")))
+    }
+    if len(expiring) > 0 {
+        recommendations = append(recommendations,
+            fmt.Sprintf("Renew soon: %s", strings.Join(expiring, ", ")))
+    }
 
-Job Title: %s
-Company: %s
-Job Description: %s
-My Profile: %s
+    return recommendations
+}
 
-Please write a compelling, professional cover letter that highlights relevant skills and experience. Focus on cybersecurity aspects mentioned in the job description. Keep it concise (250-300 words) and tailored to the specific role.`, jobTitle, company, jobDescription, userProfile)
+// Defaults for CoverLetterInput
+const (
+    DefaultTone   = "professional"
+    DefaultLength = 300
+)
 
-    resp, err := g.client.CreateChatCompletion(
-        context.Background(),
-        openai.ChatCompletionRequest{
-            Model: openai.GPT3Dot5Turbo,
-            Messages: []openai.ChatCompletionMessage{
-                {
-                    Role:    openai.ChatMessageRoleUser,
-                    Content: prompt,
-                },
-            },
-            MaxTokens: 500,
-        },
-    )
+// CoverLetterInput holds the variables available to the cover letter
+// templates, e.g. {{.Job}} and {{.Length}}
+type CoverLetterInput struct {
+    Name        string // the user's name, for signing
+    Job         string // the job title
+    Company     string
+    Description string
+    Profile     string
+    Tone        string
+    Length      int // in words
+}
 
-    if err != nil {
-        return g.generateFallbackCoverLetter(jobTitle, company, jobDescription), err
+func (input CoverLetterInput) withDefaults() CoverLetterInput {
+    if input.Tone == "" {
+        input.Tone = DefaultTone
     }
+    if input.Length <= 0 {
+        input.Length = DefaultLength
+    }
+    return input
+}
 
-    return resp.Choices[0].Message.Content, nil
+// SetPrompts replaces the built-in prompt templates
+func (g *AIGenerator) SetPrompts(prompts *Prompts) {
+    g.prompts = prompts
 }
 
-func (g *AIGenerator) generateFallbackCoverLetter(jobTitle, company, jobDescription string) string {
-    return fmt.Sprintf(`
-Dear Hiring Manager,
+// Prompts returns the templates the generator writes with
+func (g *AIGenerator) Prompts() *Prompts {
+    return g.prompts
+}
+
+// GenerateCoverLetter writes a cover letter with the configured provider.
+// Provider failures are returned as *Error along with the template letter.
+func (g *AIGenerator) GenerateCoverLetter(ctx context.Context, input CoverLetterInput) (string, error) {
+    input = input.withDefaults()
+    if g.provider == nil {
+        return g.prompts.Render(PromptCoverLetterFallback, input)
+    }
+
+    prompt, err := g.prompts.Render(PromptCoverLetter, input)
+    if err != nil {
+        return "", err
+    }
+    letter, err := g.provider.Complete(ctx, CompletionRequest{Prompt: prompt})
+    if err != nil {
+        fallback, _ := g.prompts.Render(PromptCoverLetterFallback, input)
+        return fallback, err
+    }
 
-I am writing to express my enthusiastic interest in the %s position at %s, as advertised. With my comprehensive background in cybersecurity and cloud security, I am confident in my ability to contribute significantly to your security initiatives.
+    return letter, nil
+}
 
-My experience encompasses working with Fortinet firewalls, AWS security services, SIEM solutions, and implementing robust security practices in cloud environments. I have hands-on expertise in security monitoring, incident response, vulnerability management, and threat intelligence.
+// StreamCoverLetter is GenerateCoverLetter, calling onDelta with each piece
+// of the letter as it is written. Without a provider the template letter is
+// sent in one piece. On failure the text sent so far should be discarded.
+func (g *AIGenerator) StreamCoverLetter(ctx context.Context, input CoverLetterInput, onDelta func(string)) (string, error) {
+    input = input.withDefaults()
+    if g.provider == nil {
+        letter, err := g.prompts.Render(PromptCoverLetterFallback, input)
+        if err != nil {
+            return "", err
+        }
+        onDelta(letter)
+        return letter, nil
+    }
 
-Having reviewed the job description, I am particularly excited about the opportunity to apply my skills in %s. My technical proficiencies align well with your requirements, and I am eager to bring my dedication to security excellence to your organization.
+    prompt, err := g.prompts.Render(PromptCoverLetter, input)
+    if err != nil {
+        return "", err
+    }
+    return StreamCompletion(ctx, g.provider, CompletionRequest{Prompt: prompt}, onDelta)
+}
 
-Thank you for considering my application. I look forward to the opportunity to discuss how my skills and experience can benefit %s's security objectives.
+// ModelName identifies what writes the letters: the provider's name, or
+// "template" when there is no provider
+func (g *AIGenerator) ModelName() string {
+    if g.provider == nil {
+        return "template"
+    }
+    return g.provider.Name()
+}
 
-Sincerely,
-[Your Name]
-`, jobTitle, company, company, company)
-}
\ No newline at end of file
+// HasProvider reports whether an LLM provider is configured, for features
+// that have no template fallback
+func (g *AIGenerator) HasProvider() bool {
+    return g.provider != nil
+}
@@ -0,0 +1,29 @@
+package ai
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+func TestGenerateSkillsAnalysisWholeWords(t *testing.T) {
+	g := NewAIGenerator(nil)
+	user := &models.UserProfile{
+		Summary: "Good at building relationships in digital teams, most recently as an associate.",
+	}
+
+	analysis := g.GenerateSkillsAnalysis("Requirements: Go, Git, IPS and SOC experience. Linux a must.", []string{"Linux"}, nil, user)
+
+	if !slices.Contains(analysis.MatchingSkills, "Linux") {
+		t.Errorf("MatchingSkills = %v, want Linux", analysis.MatchingSkills)
+	}
+	for _, skill := range []string{"Go", "Git", "Ips", "Soc"} {
+		if slices.Contains(analysis.MatchingSkills, skill) {
+			t.Errorf("%s matched from the profile text: %v", skill, analysis.MatchingSkills)
+		}
+		if !slices.Contains(analysis.MissingSkills, skill) {
+			t.Errorf("MissingSkills = %v, want %s", analysis.MissingSkills, skill)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"context"
+	"errors"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/briefing"
+)
+
+// companySummaryTokens is enough for a few short paragraphs
+const companySummaryTokens = 700
+
+// ErrNoProvider is returned by features that need an LLM provider when none
+// is configured
+var ErrNoProvider = errors.New("no AI provider configured")
+
+// GenerateCompanySummary has the provider summarize a company briefing in
+// Markdown. There is no template fallback, since the briefing itself is
+// already shown; without a provider it returns ErrNoProvider.
+func (g *AIGenerator) GenerateCompanySummary(ctx context.Context, input briefing.Briefing) (string, error) {
+	if g.provider == nil {
+		return "", ErrNoProvider
+	}
+
+	prompt, err := g.prompts.Render(PromptCompanyBriefing, input)
+	if err != nil {
+		return "", err
+	}
+	summary, err := g.provider.Complete(ctx, CompletionRequest{
+		System:    "You brief job seekers on employers. You only use the facts you are given and say when something is not known.",
+		Prompt:    prompt,
+		MaxTokens: companySummaryTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return stripCodeFence(summary), nil
+}
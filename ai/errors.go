@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrorKind classifies why a model call failed
+type ErrorKind int
+
+const (
+	ErrUnknown       ErrorKind = iota
+	ErrRateLimited             // the provider asked us to slow down
+	ErrTimeout                 // the call took longer than the configured timeout
+	ErrCanceled                // the caller gave up, e.g. the client disconnected
+	ErrUnavailable             // the provider could not be reached or failed (5xx)
+	ErrUnauthorized            // the API key was missing or rejected
+	ErrBadRequest              // the provider rejected the request itself
+	ErrEmptyResponse           // the provider answered with no text
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrRateLimited:
+		return "rate limited"
+	case ErrTimeout:
+		return "timed out"
+	case ErrCanceled:
+		return "canceled"
+	case ErrUnavailable:
+		return "unavailable"
+	case ErrUnauthorized:
+		return "unauthorized"
+	case ErrBadRequest:
+		return "bad request"
+	case ErrEmptyResponse:
+		return "empty response"
+	default:
+		return "failed"
+	}
+}
+
+// Error is returned by providers and the generator for failed model calls
+type Error struct {
+	Kind     ErrorKind
+	Provider string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s: %s", e.Provider, e.Kind)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether trying again later may succeed
+func (e *Error) Retryable() bool {
+	return e.Kind == ErrRateLimited || e.Kind == ErrUnavailable
+}
+
+// KindOf returns the kind of a model error, or ErrUnknown for other errors
+func KindOf(err error) ErrorKind {
+	var aiErr *Error
+	if errors.As(err, &aiErr) {
+		return aiErr.Kind
+	}
+	return ErrUnknown
+}
+
+// classifyError wraps an error from the OpenAI client in an *Error
+func classifyError(provider string, err error) error {
+	var aiErr *Error
+	if err == nil || errors.As(err, &aiErr) {
+		return err
+	}
+
+	kind := ErrUnknown
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		kind = ErrTimeout
+	case errors.Is(err, context.Canceled):
+		kind = ErrCanceled
+	case errors.As(err, &apiErr):
+		kind = kindForStatus(apiErr.HTTPStatusCode)
+	case errors.As(err, &reqErr):
+		kind = kindForStatus(reqErr.HTTPStatusCode)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		kind = ErrTimeout
+	case errors.As(err, &netErr):
+		kind = ErrUnavailable
+	}
+	return &Error{Kind: kind, Provider: provider, Err: err}
+}
+
+func kindForStatus(status int) ErrorKind {
+	switch {
+	case status == 429:
+		return ErrRateLimited
+	case status == 401 || status == 403:
+		return ErrUnauthorized
+	case status == 408 || status == 504:
+		return ErrTimeout
+	case status >= 500:
+		return ErrUnavailable
+	case status >= 400:
+		return ErrBadRequest
+	default:
+		return ErrUnknown
+	}
+}
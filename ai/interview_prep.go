@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// interviewPrepTokens leaves room for a pack of around twenty questions
+const interviewPrepTokens = 1800
+
+// Caps on the questions drawn from the job, so the pack stays readable
+const (
+	maxSkillQuestions       = 4
+	maxGapQuestions         = 2
+	maxRequirementQuestions = 3
+	maxStarPrompts          = 5
+)
+
+// InterviewPrepInput holds the variables available to the interview prep
+// templates. The question lists are filled in by Prepared from the job and
+// the user's work history.
+type InterviewPrepInput struct {
+	Name           string
+	Job            string // the job title
+	Company        string
+	Description    string
+	HiringManager  string
+	Skills         []string // skills the job asks for
+	Requirements   []string // lines of the description's requirements section
+	MatchingSkills []string // skills the job asks for that the user has
+	MissingSkills  []string
+	WorkHistory    []models.WorkExperience
+	CompanyNotes   []string // what we know about the company and the role
+
+	TechnicalQuestions  []string
+	BehavioralQuestions []string
+	StarPrompts         []StarPrompt
+	QuestionsToAsk      []string
+}
+
+// StarPrompt suggests a story to prepare in Situation, Task, Action, Result
+// form, drawn from one of the user's own highlights where there is one
+type StarPrompt struct {
+	Theme     string // the skill or quality the story shows
+	Position  string // "Title at Company", empty without work history
+	Highlight string
+}
+
+// Prepared fills in the question lists. Technical questions come from the
+// matching skills, the skills the user lacks and the job's requirements;
+// STAR prompts pair each matching skill with a highlight that mentions it,
+// then fall back to each position's first highlight.
+func (input InterviewPrepInput) Prepared() InterviewPrepInput {
+	input.TechnicalQuestions = nil
+	for _, skill := range firstN(input.MatchingSkills, maxSkillQuestions) {
+		input.TechnicalQuestions = append(input.TechnicalQuestions,
+			fmt.Sprintf("Walk me through a project where you used %s. What problem did it solve, and what would you do differently now?", skill))
+	}
+	for _, skill := range firstN(input.MissingSkills, maxGapQuestions) {
+		input.TechnicalQuestions = append(input.TechnicalQuestions,
+			fmt.Sprintf("This role uses %s. What have you used that is similar, and how would you get up to speed?", skill))
+	}
+	for _, requirement := range firstN(input.Requirements, maxRequirementQuestions) {
+		input.TechnicalQuestions = append(input.TechnicalQuestions,
+			fmt.Sprintf("Tell me about your experience with this: \"%s\"", strings.TrimRight(requirement, ".;")))
+	}
+	if len(input.TechnicalQuestions) == 0 {
+		input.TechnicalQuestions = []string{
+			"Describe the piece of technical work you are proudest of and the decisions behind it.",
+			"How do you approach a problem in an area you have not worked in before?",
+		}
+	}
+
+	input.BehavioralQuestions = []string{
+		"Tell me about yourself and why you applied for this role.",
+		fmt.Sprintf("Why do you want to work at %s?", input.Company),
+		"Tell me about a time you disagreed with a colleague. How did you resolve it?",
+		"Describe a mistake you made at work and what you learned from it.",
+		"Tell me about a time you had to deliver under a tight deadline.",
+		"Give an example of when you had to learn something new quickly.",
+	}
+
+	input.StarPrompts = nil
+	used := map[string]bool{}
+	for _, skill := range input.MatchingSkills {
+		if len(input.StarPrompts) >= maxStarPrompts {
+			break
+		}
+		position, highlight := highlightMentioning(input.WorkHistory, skill, used)
+		input.StarPrompts = append(input.StarPrompts, StarPrompt{Theme: skill, Position: position, Highlight: highlight})
+	}
+	for _, job := range input.WorkHistory {
+		if len(input.StarPrompts) >= maxStarPrompts {
+			break
+		}
+		for _, highlight := range job.Highlights {
+			if !used[highlight] {
+				used[highlight] = true
+				input.StarPrompts = append(input.StarPrompts, StarPrompt{Theme: "Impact", Position: positionName(job), Highlight: highlight})
+				break
+			}
+		}
+	}
+
+	input.QuestionsToAsk = []string{
+		fmt.Sprintf("What does success look like for the %s in the first 90 days?", input.Job),
+		"What are the biggest challenges the team is facing right now?",
+		"How is the team structured, and who would I work with most closely?",
+	}
+	if len(input.Skills) > 0 {
+		input.QuestionsToAsk = append(input.QuestionsToAsk,
+			fmt.Sprintf("How does the team use %s day to day, and what would you like to change about the setup?", input.Skills[0]))
+	}
+	input.QuestionsToAsk = append(input.QuestionsToAsk,
+		"How do you support learning and professional development, including certifications?",
+		"What are the next steps in the process, and when can I expect to hear back?",
+	)
+	return input
+}
+
+// GenerateInterviewPrep writes a Markdown prep pack for an interview.
+// Without a provider the prepared questions are laid out as they are.
+// Provider failures are returned as *Error along with that pack.
+func (g *AIGenerator) GenerateInterviewPrep(ctx context.Context, input InterviewPrepInput) (string, error) {
+	input = input.Prepared()
+	if g.provider == nil {
+		return g.prompts.Render(PromptInterviewPrepFallback, input)
+	}
+
+	prompt, err := g.prompts.Render(PromptInterviewPrep, input)
+	if err != nil {
+		return "", err
+	}
+	pack, err := g.provider.Complete(ctx, CompletionRequest{
+		System:    "You are an interview coach. You only use the facts you are given about the candidate and the company.",
+		Prompt:    prompt,
+		MaxTokens: interviewPrepTokens,
+	})
+	if err != nil {
+		fallback, _ := g.prompts.Render(PromptInterviewPrepFallback, input)
+		return fallback, err
+	}
+
+	return stripCodeFence(pack), nil
+}
+
+// highlightMentioning finds the first unused highlight that mentions skill
+// and marks it used
+func highlightMentioning(history []models.WorkExperience, skill string, used map[string]bool) (string, string) {
+	for _, job := range history {
+		for _, highlight := range job.Highlights {
+			if !used[highlight] && skillMentions(highlight, []string{skill}) > 0 {
+				used[highlight] = true
+				return positionName(job), highlight
+			}
+		}
+	}
+	return "", ""
+}
+
+func positionName(job models.WorkExperience) string {
+	if job.Company == "" {
+		return job.Title
+	}
+	return job.Title + " at " + job.Company
+}
+
+func firstN(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
@@ -0,0 +1,242 @@
+package ai
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/briefing"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"gorm.io/datatypes"
+)
+
+// Names of the prompt templates. Each is a text/template file named
+// <name>.tmpl.
+const (
+	PromptCoverLetter            = "cover_letter"
+	PromptCoverLetterFallback    = "cover_letter_fallback"
+	PromptTailoredResume         = "tailored_resume"
+	PromptTailoredResumeFallback = "tailored_resume_fallback"
+	PromptInterviewPrep          = "interview_prep"
+	PromptInterviewPrepFallback  = "interview_prep_fallback"
+	PromptCompanyBriefing        = "company_briefing"
+)
+
+// PromptNames lists every template, in the order the settings page shows them
+var PromptNames = []string{
+	PromptCoverLetter, PromptCoverLetterFallback,
+	PromptTailoredResume, PromptTailoredResumeFallback,
+	PromptInterviewPrep, PromptInterviewPrepFallback,
+	PromptCompanyBriefing,
+}
+
+// promptSamples is the data each template is checked against before an
+// override is accepted
+var promptSamples = map[string]interface{}{
+	PromptCoverLetter:            sampleCoverLetterInput,
+	PromptCoverLetterFallback:    sampleCoverLetterInput,
+	PromptTailoredResume:         sampleTailoredResumeInput,
+	PromptTailoredResumeFallback: sampleTailoredResumeInput,
+	PromptInterviewPrep:          sampleInterviewPrepInput,
+	PromptInterviewPrepFallback:  sampleInterviewPrepInput,
+	PromptCompanyBriefing:        sampleCompanyBriefing,
+}
+
+var sampleCoverLetterInput = CoverLetterInput{
+	Name:        "Jane Doe",
+	Job:         "Data Analyst",
+	Company:     "Acme",
+	Description: "Build dashboards and reports from sales data.",
+	Profile:     "Analyst with three years of SQL and Python.",
+	Tone:        DefaultTone,
+	Length:      DefaultLength,
+}
+
+var sampleTailoredResumeInput = TailoredResumeInput{
+	Name:           "Jane Doe",
+	Headline:       "Data analyst",
+	Contact:        []string{"jane@example.com", "Nairobi"},
+	Job:            "Data Analyst",
+	Company:        "Acme",
+	Description:    "Build dashboards and reports from sales data.",
+	Summary:        "Analyst with three years of SQL and Python.",
+	MatchingSkills: []string{"SQL"},
+	Skills:         []string{"SQL", "Python"},
+	Certifications: []string{"Google Data Analytics"},
+	WorkHistory: []models.WorkExperience{{
+		Title: "Analyst", Company: "Globex", Start: "2021-01",
+		Highlights: []string{"Built weekly sales dashboards in SQL"},
+	}},
+	Education:  []models.Education{{Qualification: "BSc Statistics", Institution: "University of Nairobi", Year: "2020"}},
+	ResumeText: "Jane Doe\nAnalyst, Globex, 2021 - present\nBuilt weekly sales dashboards in SQL",
+}
+
+var sampleInterviewPrepInput = InterviewPrepInput{
+	Name:           "Jane Doe",
+	Job:            "Data Analyst",
+	Company:        "Acme",
+	Description:    "Build dashboards and reports from sales data.",
+	HiringManager:  "John Smith",
+	Skills:         []string{"SQL", "Tableau"},
+	Requirements:   []string{"3+ years of SQL"},
+	MatchingSkills: []string{"SQL"},
+	MissingSkills:  []string{"Tableau"},
+	WorkHistory:    sampleTailoredResumeInput.WorkHistory,
+	CompanyNotes:   []string{"Based in Nairobi"},
+}.Prepared()
+
+var sampleCompanyBriefing = briefing.Build("Acme",
+	[]models.Job{{
+		ID: "1", Title: "Data Analyst", Company: "Acme", Location: "Nairobi", PostedDate: "2024-05-01",
+		SalaryRange: "Ksh 80,000 - Ksh 120,000", TechStack: datatypes.JSON(`["SQL","Tableau"]`),
+	}},
+	[]models.Application{{ID: "1", Company: "Acme", Role: "Data Analyst", AppliedDate: "2024-05-03", Status: "Applied"}},
+	"Met their team at a meetup.", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC))
+
+// promptFuncs are the functions templates can call
+var promptFuncs = template.FuncMap{"join": strings.Join, "date": formatMonth}
+
+//go:embed prompts/*.tmpl
+var defaultPrompts embed.FS
+
+// Where a template's text came from
+const (
+	PromptOriginDefault = "default"
+	PromptOriginFile    = "file"
+	PromptOriginUser    = "user"
+)
+
+// Prompts resolves templates by name. A user override wins over a file in
+// the prompts directory, which wins over the built-in default. Files are
+// read on every use so edits apply without a restart.
+type Prompts struct {
+	dir       string
+	mu        sync.RWMutex
+	overrides map[string]string
+}
+
+// NewPrompts reads templates from dir, which may be empty to use only the
+// defaults and overrides
+func NewPrompts(dir string) *Prompts {
+	return &Prompts{dir: dir, overrides: map[string]string{}}
+}
+
+// NewPromptsFromEnv uses the directory in PROMPTS_DIR, checking that every
+// template in it parses
+func NewPromptsFromEnv() (*Prompts, error) {
+	dir := os.Getenv("PROMPTS_DIR")
+	if dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("PROMPTS_DIR %q is not a directory", dir)
+		}
+	}
+
+	prompts := NewPrompts(dir)
+	for _, name := range PromptNames {
+		text, _, err := prompts.Source(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := ValidatePrompt(name, text); err != nil {
+			return nil, err
+		}
+	}
+	return prompts, nil
+}
+
+// SetOverride replaces a template with the user's own text, or restores the
+// file or default when text is empty
+func (p *Prompts) SetOverride(name, text string) error {
+	if strings.TrimSpace(text) == "" {
+		p.mu.Lock()
+		delete(p.overrides, name)
+		p.mu.Unlock()
+		return nil
+	}
+	if err := ValidatePrompt(name, text); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.overrides[name] = text
+	p.mu.Unlock()
+	return nil
+}
+
+// Source returns a template's text and where it came from
+func (p *Prompts) Source(name string) (string, string, error) {
+	if _, ok := promptSamples[name]; !ok {
+		return "", "", fmt.Errorf("unknown prompt %q", name)
+	}
+
+	p.mu.RLock()
+	text, ok := p.overrides[name]
+	p.mu.RUnlock()
+	if ok {
+		return text, PromptOriginUser, nil
+	}
+
+	if p.dir != "" {
+		data, err := os.ReadFile(filepath.Join(p.dir, name+".tmpl"))
+		if err == nil {
+			return string(data), PromptOriginFile, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", "", fmt.Errorf("failed to read prompt %s: %v", name, err)
+		}
+	}
+
+	data, err := defaultPrompts.ReadFile("prompts/" + name + ".tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("no default for prompt %s: %v", name, err)
+	}
+	return string(data), PromptOriginDefault, nil
+}
+
+// Render executes a template with data
+func (p *Prompts) Render(name string, data interface{}) (string, error) {
+	text, _, err := p.Source(name)
+	if err != nil {
+		return "", err
+	}
+	return renderPrompt(name, text, data)
+}
+
+// ValidatePrompt checks that text parses and runs against sample data, so a
+// typo in a variable name is caught when the template is saved rather than
+// when a letter is written
+func ValidatePrompt(name, text string) error {
+	sample, ok := promptSamples[name]
+	if !ok {
+		return fmt.Errorf("unknown prompt %q", name)
+	}
+	_, err := renderPrompt(name, text, sample)
+	return err
+}
+
+// formatMonth turns a profile date such as 2021-03 into "Mar 2021", leaving
+// anything else as it is
+func formatMonth(date string) string {
+	if t, err := time.Parse("2006-01", date); err == nil {
+		return t.Format("Jan 2006")
+	}
+	return date
+}
+
+func renderPrompt(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(promptFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("prompt %s: %v", name, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("prompt %s: %v", name, err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
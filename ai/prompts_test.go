@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePrompt(t *testing.T, dir, name, text string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+".tmpl"), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromptsSource(t *testing.T) {
+	dir := t.TempDir()
+	writePrompt(t, dir, PromptCoverLetter, "From file for {{.Company}}")
+
+	tests := []struct {
+		name     string
+		dir      string
+		override string
+		prompt   string
+		want     string
+		origin   string
+	}{
+		{name: "default", prompt: PromptCoverLetter, origin: PromptOriginDefault},
+		{name: "file beats default", dir: dir, prompt: PromptCoverLetter, want: "From file for {{.Company}}", origin: PromptOriginFile},
+		{name: "no file falls back to default", dir: dir, prompt: PromptInterviewPrep, origin: PromptOriginDefault},
+		{name: "override beats file", dir: dir, override: "Override for {{.Company}}", prompt: PromptCoverLetter, want: "Override for {{.Company}}", origin: PromptOriginUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompts := NewPrompts(tt.dir)
+			if tt.override != "" {
+				if err := prompts.SetOverride(tt.prompt, tt.override); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			text, origin, err := prompts.Source(tt.prompt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if origin != tt.origin {
+				t.Errorf("origin = %q, want %q", origin, tt.origin)
+			}
+			if tt.want != "" && text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			if tt.want == "" && strings.TrimSpace(text) == "" {
+				t.Error("default template is empty")
+			}
+		})
+	}
+}
+
+func TestPromptsOverrideCleared(t *testing.T) {
+	dir := t.TempDir()
+	writePrompt(t, dir, PromptCoverLetter, "From file for {{.Company}}")
+	prompts := NewPrompts(dir)
+
+	prompts.SetOverride(PromptCoverLetter, "Override for {{.Company}}")
+	prompts.SetOverride(PromptCoverLetter, "  ")
+	if _, origin, _ := prompts.Source(PromptCoverLetter); origin != PromptOriginFile {
+		t.Errorf("origin after clearing the override = %q, want file", origin)
+	}
+
+	// Files are read on every use
+	writePrompt(t, dir, PromptCoverLetter, "Edited for {{.Company}}")
+	if got, err := prompts.Render(PromptCoverLetter, sampleCoverLetterInput); err != nil || got != "Edited for Acme" {
+		t.Errorf("Render = %q, %v", got, err)
+	}
+
+	if _, _, err := prompts.Source("no_such_prompt"); err == nil {
+		t.Error("Source accepted an unknown prompt")
+	}
+}
+
+func TestValidatePrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		prompt  string
+		text    string
+		wantErr bool
+	}{
+		{"valid", PromptCoverLetter, "Dear {{.Company}}, I am {{.Name}}.", false},
+		{"functions", PromptTailoredResume, "{{join .Skills \", \"}} since {{date \"2021-03\"}}", false},
+		{"misspelt field", PromptCoverLetter, "Dear {{.Compnay}}", true},
+		{"field from another prompt", PromptCoverLetter, "{{.MissingSkills}}", true},
+		{"unclosed action", PromptCoverLetter, "Dear {{.Company", true},
+		{"unknown function", PromptCoverLetter, "{{upper .Company}}", true},
+		{"unknown prompt", "no_such_prompt", "Hello", true},
+	}
+	for _, tt := range tests {
+		err := ValidatePrompt(tt.prompt, tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidatePrompt error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Every built-in default validates
+	for _, name := range PromptNames {
+		text, _, err := NewPrompts("").Source(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidatePrompt(name, text); err != nil {
+			t.Errorf("default %s: %v", name, err)
+		}
+	}
+}
+
+func TestSetOverrideRejectsInvalidTemplate(t *testing.T) {
+	prompts := NewPrompts("")
+	if err := prompts.SetOverride(PromptCoverLetter, "Dear {{.Compnay}}"); err == nil {
+		t.Fatal("SetOverride accepted a misspelt field")
+	}
+	if _, origin, _ := prompts.Source(PromptCoverLetter); origin != PromptOriginDefault {
+		t.Errorf("origin = %q, want the default kept", origin)
+	}
+}
+
+func TestNewPromptsFromEnv(t *testing.T) {
+	valid := t.TempDir()
+	writePrompt(t, valid, PromptCoverLetter, "From file for {{.Company}}")
+	broken := t.TempDir()
+	writePrompt(t, broken, PromptInterviewPrep, "Prep for {{.Compnay}}")
+	notDir := filepath.Join(t.TempDir(), "prompts.txt")
+	os.WriteFile(notDir, []byte("not a directory"), 0o644)
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{"unset", "", false},
+		{"valid directory", valid, false},
+		{"missing directory", filepath.Join(valid, "missing"), true},
+		{"file instead of directory", notDir, true},
+		{"broken template", broken, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROMPTS_DIR", tt.dir)
+			prompts, err := NewPromptsFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && tt.dir != "" {
+				if _, origin, _ := prompts.Source(PromptCoverLetter); origin != PromptOriginFile {
+					t.Errorf("origin = %q, want file", origin)
+				}
+			}
+		})
+	}
+}
+
+func TestTailoredResumePromptResumeText(t *testing.T) {
+	prompts := NewPrompts("")
+	prompt, err := prompts.Render(PromptTailoredResume, sampleTailoredResumeInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt, "My current resume") || !strings.Contains(prompt, sampleTailoredResumeInput.ResumeText) {
+		t.Errorf("prompt is missing the resume text:\n%s", prompt)
+	}
+
+	input := sampleTailoredResumeInput
+	input.ResumeText = ""
+	if prompt, _ := prompts.Render(PromptTailoredResume, input); strings.Contains(prompt, "My current resume") {
+		t.Errorf("prompt mentions a resume that was not uploaded:\n%s", prompt)
+	}
+}
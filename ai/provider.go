@@ -0,0 +1,271 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Defaults used when the environment does not say otherwise
+const (
+	DefaultModel       = openai.GPT3Dot5Turbo
+	DefaultTemperature = 0.7
+	DefaultMaxTokens   = 500
+)
+
+// CompletionRequest is a single prompt sent to a language model
+type CompletionRequest struct {
+	System string // optional system message
+	Prompt string
+	// MaxTokens and Temperature override the provider's configured values
+	// when set
+	MaxTokens   int
+	Temperature *float32
+}
+
+// LLMProvider generates text from a prompt
+type LLMProvider interface {
+	Complete(ctx context.Context, req CompletionRequest) (string, error)
+	// Name identifies the provider and model, e.g. "openai/gpt-3.5-turbo"
+	Name() string
+}
+
+// LLMConfig chooses and configures a provider
+type LLMConfig struct {
+	Provider    string // openai, fake or none
+	BaseURL     string // for OpenAI-compatible servers such as Ollama or vLLM
+	APIKey      string
+	Model       string
+	Temperature float32
+	MaxTokens   int
+	Timeout     time.Duration // per attempt
+	MaxRetries  int           // on rate limits and transient failures
+}
+
+// LoadLLMConfig reads the provider configuration from the environment:
+//
+//	LLM_PROVIDER     openai (any OpenAI-compatible endpoint), fake or none;
+//	                 defaults to openai when an API key or base URL is set
+//	LLM_BASE_URL     e.g. http://localhost:11434/v1 for Ollama
+//	LLM_API_KEY      falls back to OPENAI_API_KEY
+//	LLM_MODEL        defaults to gpt-3.5-turbo
+//	LLM_TEMPERATURE  defaults to 0.7
+//	LLM_MAX_TOKENS   defaults to 500
+//	LLM_TIMEOUT      seconds per attempt, defaults to 30
+//	LLM_MAX_RETRIES  retries on rate limits and provider errors, defaults to 2
+func LoadLLMConfig() (LLMConfig, error) {
+	config := LLMConfig{
+		Provider:    strings.ToLower(os.Getenv("LLM_PROVIDER")),
+		BaseURL:     os.Getenv("LLM_BASE_URL"),
+		APIKey:      os.Getenv("LLM_API_KEY"),
+		Model:       os.Getenv("LLM_MODEL"),
+		Temperature: DefaultTemperature,
+		MaxTokens:   DefaultMaxTokens,
+		Timeout:     DefaultTimeout,
+		MaxRetries:  DefaultMaxRetries,
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if config.Model == "" {
+		config.Model = DefaultModel
+	}
+	if config.Provider == "" {
+		config.Provider = "none"
+		if config.APIKey != "" || config.BaseURL != "" {
+			config.Provider = "openai"
+		}
+	}
+
+	if value := os.Getenv("LLM_TEMPERATURE"); value != "" {
+		temperature, err := strconv.ParseFloat(value, 32)
+		if err != nil || temperature < 0 || temperature > 2 {
+			return config, fmt.Errorf("LLM_TEMPERATURE must be a number between 0 and 2, got %q", value)
+		}
+		config.Temperature = float32(temperature)
+	}
+	if value := os.Getenv("LLM_MAX_TOKENS"); value != "" {
+		maxTokens, err := strconv.Atoi(value)
+		if err != nil || maxTokens <= 0 {
+			return config, fmt.Errorf("LLM_MAX_TOKENS must be a positive number, got %q", value)
+		}
+		config.MaxTokens = maxTokens
+	}
+	if value := os.Getenv("LLM_TIMEOUT"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return config, fmt.Errorf("LLM_TIMEOUT must be a positive number of seconds, got %q", value)
+		}
+		config.Timeout = time.Duration(seconds) * time.Second
+	}
+	if value := os.Getenv("LLM_MAX_RETRIES"); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return config, fmt.Errorf("LLM_MAX_RETRIES must be 0 or more, got %q", value)
+		}
+		config.MaxRetries = retries
+	}
+	return config, nil
+}
+
+// NewLLMProvider builds the configured provider, wrapped with the
+// configured timeout and retries. It returns nil for "none", in which case
+// the generator falls back to templates.
+func NewLLMProvider(config LLMConfig) (LLMProvider, error) {
+	var provider LLMProvider
+	switch config.Provider {
+	case "none":
+		return nil, nil
+	case "openai":
+		if config.APIKey == "" && config.BaseURL == "" {
+			return nil, fmt.Errorf("the openai provider needs LLM_API_KEY, OPENAI_API_KEY or LLM_BASE_URL")
+		}
+		provider = NewOpenAIProvider(config)
+	case "fake":
+		provider = NewFakeProvider()
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER %q (want openai, fake or none)", config.Provider)
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return WithRetries(provider, config.Timeout, config.MaxRetries), nil
+}
+
+// NewLLMProviderFromEnv is LoadLLMConfig followed by NewLLMProvider
+func NewLLMProviderFromEnv() (LLMProvider, error) {
+	config, err := LoadLLMConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewLLMProvider(config)
+}
+
+// OpenAIProvider talks to the OpenAI chat completions API, or any server
+// implementing it when a base URL is set
+type OpenAIProvider struct {
+	client *openai.Client
+	config LLMConfig
+}
+
+func NewOpenAIProvider(config LLMConfig) *OpenAIProvider {
+	clientConfig := openai.DefaultConfig(config.APIKey)
+	if config.BaseURL != "" {
+		clientConfig.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	}
+	return &OpenAIProvider{client: openai.NewClientWithConfig(clientConfig), config: config}
+}
+
+func (p *OpenAIProvider) Name() string {
+	if p.config.BaseURL != "" {
+		return p.config.BaseURL + "/" + p.config.Model
+	}
+	return "openai/" + p.config.Model
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	resp, err := p.client.CreateChatCompletion(ctx, p.chatRequest(req))
+	if err != nil {
+		return "", classifyError(p.Name(), err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", &Error{Kind: ErrEmptyResponse, Provider: p.Name(), Err: errors.New("no choices returned")}
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// chatRequest builds the API request, applying the per-request overrides
+func (p *OpenAIProvider) chatRequest(req CompletionRequest) openai.ChatCompletionRequest {
+	var messages []openai.ChatCompletionMessage
+	if req.System != "" {
+		messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: req.System})
+	}
+	messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: req.Prompt})
+
+	maxTokens, temperature := p.config.MaxTokens, p.config.Temperature
+	if req.MaxTokens > 0 {
+		maxTokens = req.MaxTokens
+	}
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+
+	return openai.ChatCompletionRequest{
+		Model:       p.config.Model,
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+}
+
+// FakeProvider returns canned responses without any network access, for
+// tests and offline development. With no responses set it echoes a summary
+// of the prompt, so the same prompt always gives the same text.
+type FakeProvider struct {
+	mu        sync.Mutex
+	responses []string
+	served    int     // responses returned so far
+	failures  []error // errors for the next calls to return
+	calls     []CompletionRequest
+}
+
+// NewFakeProvider returns the responses in order, repeating the last one
+func NewFakeProvider(responses ...string) *FakeProvider {
+	return &FakeProvider{responses: responses}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", classifyError(p.Name(), err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls = append(p.calls, req)
+
+	if len(p.failures) > 0 {
+		err := p.failures[0]
+		p.failures = p.failures[1:]
+		return "", classifyError(p.Name(), err)
+	}
+
+	if len(p.responses) > 0 {
+		response := p.responses[min(p.served, len(p.responses)-1)]
+		p.served++
+		return response, nil
+	}
+
+	prompt := strings.Join(strings.Fields(req.Prompt), " ")
+	if len(prompt) > 120 {
+		prompt = prompt[:120] + "..."
+	}
+	return fmt.Sprintf("[fake completion] %s", prompt), nil
+}
+
+// Fail makes the next calls return errs, one each, before any more
+// responses are given
+func (p *FakeProvider) Fail(errs ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures = append(p.failures, errs...)
+}
+
+// Calls returns the requests made so far
+func (p *FakeProvider) Calls() []CompletionRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]CompletionRequest(nil), p.calls...)
+}
@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeProviderResponses(t *testing.T) {
+	provider := NewFakeProvider("first", "second")
+	ctx := context.Background()
+
+	for _, want := range []string{"first", "second", "second"} {
+		got, err := provider.Complete(ctx, CompletionRequest{Prompt: "p"})
+		if err != nil || got != want {
+			t.Errorf("Complete = %q, %v, want %q", got, err, want)
+		}
+	}
+	if calls := provider.Calls(); len(calls) != 3 {
+		t.Errorf("recorded %d calls, want 3", len(calls))
+	}
+}
+
+func TestFakeProviderEcho(t *testing.T) {
+	provider := NewFakeProvider()
+	req := CompletionRequest{Prompt: "Write a cover letter\n\nfor the   SOC Analyst role"}
+
+	got, err := provider.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[fake completion] Write a cover letter for the SOC Analyst role"; got != want {
+		t.Errorf("Complete = %q, want %q", got, want)
+	}
+	if again, _ := provider.Complete(context.Background(), req); again != got {
+		t.Errorf("same prompt gave %q then %q", got, again)
+	}
+
+	long, _ := provider.Complete(context.Background(), CompletionRequest{Prompt: strings.Repeat("a", 500)})
+	if !strings.HasSuffix(long, "...") || len(long) > 150 {
+		t.Errorf("long prompt not shortened: %q", long)
+	}
+}
+
+func TestFakeProviderFail(t *testing.T) {
+	provider := NewFakeProvider("ok")
+	provider.Fail(&Error{Kind: ErrRateLimited, Provider: "fake"}, errors.New("boom"))
+	ctx := context.Background()
+
+	if _, err := provider.Complete(ctx, CompletionRequest{}); KindOf(err) != ErrRateLimited {
+		t.Errorf("first call error = %v, want rate limited", err)
+	}
+	if _, err := provider.Complete(ctx, CompletionRequest{}); KindOf(err) != ErrUnknown || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("second call error = %v, want the wrapped error", err)
+	}
+	// Failures don't use up responses
+	if got, err := provider.Complete(ctx, CompletionRequest{}); err != nil || got != "ok" {
+		t.Errorf("third call = %q, %v, want ok", got, err)
+	}
+}
+
+func TestFakeProviderCanceled(t *testing.T) {
+	provider := NewFakeProvider("ok")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := provider.Complete(ctx, CompletionRequest{}); KindOf(err) != ErrCanceled {
+		t.Errorf("error = %v, want canceled", err)
+	}
+	if calls := provider.Calls(); len(calls) != 0 {
+		t.Errorf("recorded %d calls after cancellation, want 0", len(calls))
+	}
+}
+
+func TestNewLLMProvider(t *testing.T) {
+	if provider, err := NewLLMProvider(LLMConfig{Provider: "none"}); provider != nil || err != nil {
+		t.Errorf("none = %v, %v, want no provider", provider, err)
+	}
+
+	provider, err := NewLLMProvider(LLMConfig{Provider: "fake", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	retrying, ok := provider.(*RetryingProvider)
+	if !ok {
+		t.Fatalf("fake provider is %T, want it wrapped with retries", provider)
+	}
+	if retrying.Name() != "fake" || retrying.timeout != DefaultTimeout || retrying.maxRetries != 1 {
+		t.Errorf("wrapped as %s with timeout %s and %d retries", retrying.Name(), retrying.timeout, retrying.maxRetries)
+	}
+
+	if _, err := NewLLMProvider(LLMConfig{Provider: "openai"}); err == nil {
+		t.Error("openai without a key or base URL should fail")
+	}
+	if _, err := NewLLMProvider(LLMConfig{Provider: "claude"}); err == nil {
+		t.Error("an unknown provider should fail")
+	}
+}
+
+func TestLoadLLMConfig(t *testing.T) {
+	for _, name := range []string{"LLM_PROVIDER", "LLM_BASE_URL", "LLM_API_KEY", "OPENAI_API_KEY", "LLM_MODEL",
+		"LLM_TEMPERATURE", "LLM_MAX_TOKENS", "LLM_TIMEOUT", "LLM_MAX_RETRIES"} {
+		t.Setenv(name, "")
+	}
+
+	config, err := LoadLLMConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Provider != "none" || config.Model != DefaultModel || config.Timeout != DefaultTimeout || config.MaxRetries != DefaultMaxRetries {
+		t.Errorf("defaults = %+v", config)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("LLM_TIMEOUT", "5")
+	t.Setenv("LLM_MAX_RETRIES", "0")
+	if config, err = LoadLLMConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if config.Provider != "openai" || config.APIKey != "sk-test" || config.Timeout != 5*time.Second || config.MaxRetries != 0 {
+		t.Errorf("from the environment = %+v", config)
+	}
+
+	for name, value := range map[string]string{
+		"LLM_TEMPERATURE": "3",
+		"LLM_MAX_TOKENS":  "0",
+		"LLM_TIMEOUT":     "soon",
+		"LLM_MAX_RETRIES": "-1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := LoadLLMConfig(); err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("%s=%s: error = %v", name, value, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+// Defaults for RetryingProvider, overridable with LLM_TIMEOUT and
+// LLM_MAX_RETRIES
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 2
+)
+
+// retryBackoff is the wait before the first retry; it doubles each time
+var retryBackoff = time.Second
+
+// RetryingProvider gives each call to the wrapped provider a timeout and
+// retries calls that were rate limited or hit a transient failure. Every
+// error it returns is an *Error.
+type RetryingProvider struct {
+	provider   LLMProvider
+	timeout    time.Duration
+	maxRetries int
+}
+
+func WithRetries(provider LLMProvider, timeout time.Duration, maxRetries int) *RetryingProvider {
+	return &RetryingProvider{provider: provider, timeout: timeout, maxRetries: maxRetries}
+}
+
+func (p *RetryingProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *RetryingProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	return p.retry(ctx, func(attemptCtx context.Context) (string, bool, error) {
+		text, err := p.provider.Complete(attemptCtx, req)
+		return text, true, err
+	})
+}
+
+// retry runs attempt until it succeeds, fails in a way that is not worth
+// retrying, or runs out of retries. attempt reports whether its failure may
+// be retried at all.
+func (p *RetryingProvider) retry(ctx context.Context, attempt func(context.Context) (string, bool, error)) (string, error) {
+	for n := 0; ; n++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		text, canRetry, err := attempt(attemptCtx)
+		cancel()
+
+		if err == nil && strings.TrimSpace(text) == "" {
+			err = &Error{Kind: ErrEmptyResponse, Provider: p.Name()}
+		}
+		if err == nil {
+			return text, nil
+		}
+
+		// The caller's own deadline or cancellation wins over the attempt's
+		if ctx.Err() != nil {
+			return "", classifyError(p.Name(), ctx.Err())
+		}
+
+		err = classifyError(p.Name(), err)
+		var aiErr *Error
+		if !canRetry || !errors.As(err, &aiErr) || !aiErr.Retryable() || n >= p.maxRetries {
+			return "", err
+		}
+
+		delay := retryBackoff << n
+		log.Printf("⚠️ %v; retrying in %s (attempt %d of %d)", err, delay, n+2, p.maxRetries+1)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", classifyError(p.Name(), ctx.Err())
+		}
+	}
+}
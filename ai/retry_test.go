@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// withoutBackoff makes retries immediate for the length of a test
+func withoutBackoff(t *testing.T) {
+	previous := retryBackoff
+	retryBackoff = 0
+	t.Cleanup(func() { retryBackoff = previous })
+}
+
+func TestRetryingProviderRetries(t *testing.T) {
+	withoutBackoff(t)
+	fake := NewFakeProvider("Dear hiring manager")
+	fake.Fail(&Error{Kind: ErrRateLimited}, &Error{Kind: ErrUnavailable})
+
+	got, err := WithRetries(fake, time.Second, 2).Complete(context.Background(), CompletionRequest{Prompt: "p"})
+	if err != nil || got != "Dear hiring manager" {
+		t.Errorf("Complete = %q, %v", got, err)
+	}
+	if calls := len(fake.Calls()); calls != 3 {
+		t.Errorf("made %d calls, want 3", calls)
+	}
+}
+
+func TestRetryingProviderGivesUp(t *testing.T) {
+	withoutBackoff(t)
+	tests := []struct {
+		name       string
+		failures   []error
+		response   string
+		maxRetries int
+		want       ErrorKind
+		calls      int
+	}{
+		{
+			name:       "out of retries",
+			failures:   []error{&Error{Kind: ErrUnavailable}, &Error{Kind: ErrUnavailable}, &Error{Kind: ErrUnavailable}},
+			response:   "ok",
+			maxRetries: 1,
+			want:       ErrUnavailable,
+			calls:      2,
+		},
+		{
+			name:       "not retryable",
+			failures:   []error{&Error{Kind: ErrUnauthorized}},
+			response:   "ok",
+			maxRetries: 2,
+			want:       ErrUnauthorized,
+			calls:      1,
+		},
+		{
+			name:       "empty response",
+			response:   "  \n",
+			maxRetries: 2,
+			want:       ErrEmptyResponse,
+			calls:      1,
+		},
+		{
+			name:       "retries disabled",
+			failures:   []error{&Error{Kind: ErrRateLimited}},
+			response:   "ok",
+			maxRetries: 0,
+			want:       ErrRateLimited,
+			calls:      1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeProvider(tt.response)
+			fake.Fail(tt.failures...)
+
+			_, err := WithRetries(fake, time.Second, tt.maxRetries).Complete(context.Background(), CompletionRequest{})
+			if KindOf(err) != tt.want {
+				t.Errorf("error = %v, want %s", err, tt.want)
+			}
+			if calls := len(fake.Calls()); calls != tt.calls {
+				t.Errorf("made %d calls, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryingProviderStopsAtCallerDeadline(t *testing.T) {
+	previous := retryBackoff
+	retryBackoff = time.Hour
+	t.Cleanup(func() { retryBackoff = previous })
+
+	fake := NewFakeProvider("ok")
+	fake.Fail(&Error{Kind: ErrRateLimited})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := WithRetries(fake, time.Second, 2).Complete(ctx, CompletionRequest{})
+	if KindOf(err) != ErrTimeout {
+		t.Errorf("error = %v, want timed out", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %s for a retry past the caller's deadline", elapsed)
+	}
+	if calls := len(fake.Calls()); calls != 1 {
+		t.Errorf("made %d calls, want 1", calls)
+	}
+}
+
+func TestRetryingProviderCanceled(t *testing.T) {
+	withoutBackoff(t)
+	fake := NewFakeProvider("ok")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := WithRetries(fake, time.Second, 2).Complete(ctx, CompletionRequest{}); KindOf(err) != ErrCanceled {
+		t.Errorf("error = %v, want canceled", err)
+	}
+	if calls := len(fake.Calls()); calls != 0 {
+		t.Errorf("made %d calls, want none", calls)
+	}
+}
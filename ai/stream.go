@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// StreamingProvider is implemented by providers that can send text as it is
+// generated. onDelta is called with each new piece of text; Stream returns
+// the full text once the model is done.
+type StreamingProvider interface {
+	LLMProvider
+	Stream(ctx context.Context, req CompletionRequest, onDelta func(string)) (string, error)
+}
+
+// StreamCompletion streams from providers that support it and otherwise
+// sends the whole completion as a single delta
+func StreamCompletion(ctx context.Context, provider LLMProvider, req CompletionRequest, onDelta func(string)) (string, error) {
+	if streamer, ok := provider.(StreamingProvider); ok {
+		return streamer.Stream(ctx, req, onDelta)
+	}
+	text, err := provider.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	onDelta(text)
+	return text, nil
+}
+
+func (p *OpenAIProvider) Stream(ctx context.Context, req CompletionRequest, onDelta func(string)) (string, error) {
+	chatReq := p.chatRequest(req)
+	chatReq.Stream = true
+
+	stream, err := p.client.CreateChatCompletionStream(ctx, chatReq)
+	if err != nil {
+		return "", classifyError(p.Name(), err)
+	}
+	defer stream.Close()
+
+	var text strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return text.String(), classifyError(p.Name(), err)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		text.WriteString(delta)
+		onDelta(delta)
+	}
+
+	if text.Len() == 0 {
+		return "", &Error{Kind: ErrEmptyResponse, Provider: p.Name(), Err: errors.New("stream ended without any text")}
+	}
+	return text.String(), nil
+}
+
+// Stream sends the completion a word at a time
+func (p *FakeProvider) Stream(ctx context.Context, req CompletionRequest, onDelta func(string)) (string, error) {
+	text, err := p.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	for i, word := range strings.SplitAfter(text, " ") {
+		if err := ctx.Err(); err != nil {
+			return strings.Join(strings.SplitAfter(text, " ")[:i], ""), classifyError(p.Name(), err)
+		}
+		onDelta(word)
+	}
+	return text, nil
+}
+
+// Stream applies the timeout to each attempt. An attempt is only retried if
+// it failed before sending any text, since what was sent cannot be taken back.
+func (p *RetryingProvider) Stream(ctx context.Context, req CompletionRequest, onDelta func(string)) (string, error) {
+	sent := false
+	return p.retry(ctx, func(attemptCtx context.Context) (string, bool, error) {
+		text, err := StreamCompletion(attemptCtx, p.provider, req, func(delta string) {
+			sent = true
+			onDelta(delta)
+		})
+		return text, !sent, err
+	})
+}
@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// brokenStream sends the first word of the fake's completion and then fails
+type brokenStream struct {
+	*FakeProvider
+	err error
+}
+
+func (p brokenStream) Stream(ctx context.Context, req CompletionRequest, onDelta func(string)) (string, error) {
+	text, err := p.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	first := strings.SplitAfter(text, " ")[0]
+	onDelta(first)
+	return first, p.err
+}
+
+// completeOnly hides the fake's Stream method
+type completeOnly struct {
+	LLMProvider
+}
+
+func TestFakeProviderStream(t *testing.T) {
+	var deltas []string
+	got, err := NewFakeProvider("Dear hiring manager,").Stream(context.Background(), CompletionRequest{}, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil || got != "Dear hiring manager," {
+		t.Fatalf("Stream = %q, %v", got, err)
+	}
+	if want := []string{"Dear ", "hiring ", "manager,"}; strings.Join(deltas, "|") != strings.Join(want, "|") {
+		t.Errorf("deltas = %q, want %q", deltas, want)
+	}
+}
+
+func TestStreamCompletionWithoutStreaming(t *testing.T) {
+	var deltas []string
+	got, err := StreamCompletion(context.Background(), completeOnly{NewFakeProvider("Dear hiring manager,")}, CompletionRequest{}, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil || got != "Dear hiring manager," {
+		t.Fatalf("StreamCompletion = %q, %v", got, err)
+	}
+	if len(deltas) != 1 || deltas[0] != got {
+		t.Errorf("deltas = %q, want the whole text at once", deltas)
+	}
+}
+
+func TestRetryingProviderStreamRetriesBeforeText(t *testing.T) {
+	withoutBackoff(t)
+	fake := NewFakeProvider("Dear hiring manager,")
+	fake.Fail(&Error{Kind: ErrUnavailable})
+
+	var sent strings.Builder
+	got, err := WithRetries(fake, time.Second, 2).Stream(context.Background(), CompletionRequest{}, func(delta string) {
+		sent.WriteString(delta)
+	})
+	if err != nil || got != "Dear hiring manager," {
+		t.Fatalf("Stream = %q, %v", got, err)
+	}
+	if sent.String() != got {
+		t.Errorf("sent %q, want %q", sent.String(), got)
+	}
+	if calls := len(fake.Calls()); calls != 2 {
+		t.Errorf("made %d calls, want 2", calls)
+	}
+}
+
+func TestRetryingProviderStreamNoRetryAfterText(t *testing.T) {
+	withoutBackoff(t)
+	fake := NewFakeProvider("Dear hiring manager,")
+	provider := brokenStream{FakeProvider: fake, err: &Error{Kind: ErrUnavailable}}
+
+	var sent strings.Builder
+	_, err := WithRetries(provider, time.Second, 2).Stream(context.Background(), CompletionRequest{}, func(delta string) {
+		sent.WriteString(delta)
+	})
+	if KindOf(err) != ErrUnavailable {
+		t.Errorf("error = %v, want unavailable", err)
+	}
+	if calls := len(fake.Calls()); calls != 1 {
+		t.Errorf("made %d calls, want no retry once text was sent", calls)
+	}
+	if sent.String() != "Dear " {
+		t.Errorf("sent %q, want only the first attempt's text", sent.String())
+	}
+}
@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/sections"
+)
+
+// tailoredResumeTokens leaves room for a two-page resume
+const tailoredResumeTokens = 1500
+
+// tailoredResumeTemperature keeps rewrites close to the profile's wording
+var tailoredResumeTemperature float32 = 0.3
+
+// TailoredResumeInput holds the variables available to the tailored resume
+// templates. It only ever carries facts from the user's profile and their
+// latest uploaded resume.
+type TailoredResumeInput struct {
+	Name           string
+	Headline       string
+	Contact        []string // email, phone, location and links that are set
+	Job            string   // the job title
+	Company        string
+	Description    string
+	Summary        string
+	MatchingSkills []string // skills the job asks for that the user has
+	Skills         []string // all of the user's skills
+	Certifications []string
+	WorkHistory    []models.WorkExperience
+	Education      []models.Education
+	ResumeText     string // text of the latest uploaded resume, if any
+}
+
+// Tailored puts what the job asks for first: matching skills lead the skill
+// list, and within each position the highlights that mention a matching
+// skill come before the rest. Positions stay in their own order and nothing
+// is added or removed.
+func (input TailoredResumeInput) Tailored() TailoredResumeInput {
+	input.Skills = matchingFirst(input.Skills, input.MatchingSkills)
+
+	history := make([]models.WorkExperience, len(input.WorkHistory))
+	for i, job := range input.WorkHistory {
+		highlights := append([]string(nil), job.Highlights...)
+		sort.SliceStable(highlights, func(a, b int) bool {
+			return skillMentions(highlights[a], input.MatchingSkills) > skillMentions(highlights[b], input.MatchingSkills)
+		})
+		job.Highlights = highlights
+		history[i] = job
+	}
+	input.WorkHistory = history
+	return input
+}
+
+// GenerateTailoredResume rewrites the user's profile as a Markdown resume
+// for one job. Without a provider the profile is reordered but not
+// reworded. Provider failures are returned as *Error along with the
+// reordered resume.
+func (g *AIGenerator) GenerateTailoredResume(ctx context.Context, input TailoredResumeInput) (string, error) {
+	input = input.Tailored()
+	if g.provider == nil {
+		return g.prompts.Render(PromptTailoredResumeFallback, input)
+	}
+
+	prompt, err := g.prompts.Render(PromptTailoredResume, input)
+	if err != nil {
+		return "", err
+	}
+	resume, err := g.provider.Complete(ctx, CompletionRequest{
+		System:      "You rewrite resumes. You never add employers, titles, dates, qualifications, certifications, skills or achievements that are not in the material you are given.",
+		Prompt:      prompt,
+		MaxTokens:   tailoredResumeTokens,
+		Temperature: &tailoredResumeTemperature,
+	})
+	if err != nil {
+		fallback, _ := g.prompts.Render(PromptTailoredResumeFallback, input)
+		return fallback, err
+	}
+
+	return stripCodeFence(resume), nil
+}
+
+// matchingFirst orders skills so those in matching come first, keeping the
+// order within each group
+func matchingFirst(skills, matching []string) []string {
+	ordered := append([]string(nil), skills...)
+	sort.SliceStable(ordered, func(a, b int) bool {
+		return containsSkill(matching, ordered[a]) && !containsSkill(matching, ordered[b])
+	})
+	return ordered
+}
+
+func containsSkill(skills []string, skill string) bool {
+	for _, s := range skills {
+		if strings.EqualFold(s, skill) {
+			return true
+		}
+	}
+	return false
+}
+
+// skillMentions counts the skills text names as whole words
+func skillMentions(text string, skills []string) int {
+	count := 0
+	for _, skill := range skills {
+		if sections.Mentions(text, skill) {
+			count++
+		}
+	}
+	return count
+}
+
+// stripCodeFence removes the ```markdown fence models sometimes wrap their
+// answer in
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	if newline := strings.Index(text, "\n"); newline >= 0 {
+		text = text[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
@@ -0,0 +1,283 @@
+// Package briefing compiles what we know about a company from its stored
+// postings, our applications to it and the user's notes: the roles it
+// hires for, its usual tech stack, the salaries it advertises and how our
+// applications went.
+package briefing
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// maxTechStack caps the tech stack to the most common entries
+const maxTechStack = 15
+
+// minSalaryAmount ignores figures too small to be a monthly salary, such as
+// years of experience that end up in a salary string
+const minSalaryAmount = 1000
+
+// Briefing is everything stored about one company
+type Briefing struct {
+	Company         string               `json:"company"`
+	Postings        []Posting            `json:"postings"`      // newest first
+	OpenPostings    int                  `json:"open_postings"` // postings whose deadline hasn't passed
+	FirstPosted     string               `json:"first_posted"`
+	LastPosted      string               `json:"last_posted"`
+	AverageScore    int                  `json:"average_score"`
+	TechStack       []Count              `json:"tech_stack"` // most common first
+	Locations       []Count              `json:"locations"`
+	WorkModes       []Count              `json:"work_modes"`
+	EmploymentTypes []Count              `json:"employment_types"`
+	Seniority       []Count              `json:"seniority"`
+	Salary          Salary               `json:"salary"`
+	Applications    []models.Application `json:"applications"` // newest first
+	Statuses        []Count              `json:"statuses"`
+	Notes           string               `json:"notes"` // the user's notes on the company
+}
+
+// Posting is one of the company's stored jobs
+type Posting struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Location    string `json:"location"`
+	PostedDate  string `json:"posted_date"`
+	Deadline    string `json:"deadline"`
+	SalaryRange string `json:"salary_range"`
+	Score       int    `json:"score"`
+	Open        bool   `json:"open"`
+}
+
+// Count is how many postings or applications share a value
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Salary summarizes the salaries the company advertises in KSh a month.
+// Salaries in other currencies or for other periods are left out.
+type Salary struct {
+	Stated int `json:"stated"` // postings that state a monthly KSh salary
+	Min    int `json:"min"`
+	Max    int `json:"max"`
+	Median int `json:"median"` // median of each posting's midpoint
+}
+
+// Range describes the advertised salaries, or "" when none are stated
+func (s Salary) Range() string {
+	switch {
+	case s.Stated == 0:
+		return ""
+	case s.Min == s.Max:
+		return "KSh " + formatAmount(s.Min)
+	default:
+		return fmt.Sprintf("KSh %s to %s", formatAmount(s.Min), formatAmount(s.Max))
+	}
+}
+
+// MedianText is the median salary formatted like Range
+func (s Salary) MedianText() string {
+	if s.Stated == 0 {
+		return ""
+	}
+	return "KSh " + formatAmount(s.Median)
+}
+
+// Build compiles a briefing. Dates are compared with now to tell which
+// postings are still open.
+func Build(company string, jobs []models.Job, applications []models.Application, notes string, now time.Time) Briefing {
+	briefing := Briefing{Company: company, Notes: strings.TrimSpace(notes)}
+	today := now.Format("2006-01-02")
+
+	tech := newCounter()
+	locations := newCounter()
+	workModes := newCounter()
+	employmentTypes := newCounter()
+	seniority := newCounter()
+	var midpoints []int
+	totalScore := 0
+
+	for _, job := range jobs {
+		posting := Posting{
+			ID:          job.ID,
+			Title:       job.Title,
+			Location:    job.Location,
+			PostedDate:  job.PostedDate,
+			Deadline:    job.ApplicationDeadline,
+			SalaryRange: job.SalaryRange,
+			Score:       job.Score,
+			// Jobs without a stated deadline are assumed to still be open
+			Open: job.ApplicationDeadline == "" || job.ApplicationDeadline >= today,
+		}
+		briefing.Postings = append(briefing.Postings, posting)
+		if posting.Open {
+			briefing.OpenPostings++
+		}
+		totalScore += job.Score
+
+		if job.PostedDate != "" {
+			if briefing.FirstPosted == "" || job.PostedDate < briefing.FirstPosted {
+				briefing.FirstPosted = job.PostedDate
+			}
+			if job.PostedDate > briefing.LastPosted {
+				briefing.LastPosted = job.PostedDate
+			}
+		}
+
+		// A posting counts once towards each technology it mentions
+		seen := map[string]bool{}
+		for _, name := range append(stringList(job.TechStack), stringList(job.Skills)...) {
+			if key := strings.ToLower(strings.TrimSpace(name)); key != "" && !seen[key] {
+				seen[key] = true
+				tech.add(name)
+			}
+		}
+		locations.add(job.Location)
+		workModes.add(job.WorkMode)
+		employmentTypes.add(job.EmploymentType)
+		if job.Experience != "Not specified" {
+			seniority.add(job.Experience)
+		}
+
+		if low, high, ok := salaryBounds(job.SalaryRange); ok {
+			if briefing.Salary.Stated == 0 || low < briefing.Salary.Min {
+				briefing.Salary.Min = low
+			}
+			if high > briefing.Salary.Max {
+				briefing.Salary.Max = high
+			}
+			briefing.Salary.Stated++
+			midpoints = append(midpoints, (low+high)/2)
+		}
+	}
+
+	sort.SliceStable(briefing.Postings, func(i, j int) bool {
+		return briefing.Postings[i].PostedDate > briefing.Postings[j].PostedDate
+	})
+	if len(jobs) > 0 {
+		briefing.AverageScore = totalScore / len(jobs)
+	}
+	if len(midpoints) > 0 {
+		sort.Ints(midpoints)
+		middle := len(midpoints) / 2
+		briefing.Salary.Median = midpoints[middle]
+		if len(midpoints)%2 == 0 {
+			briefing.Salary.Median = (midpoints[middle-1] + midpoints[middle]) / 2
+		}
+	}
+
+	briefing.TechStack = tech.sorted()
+	if len(briefing.TechStack) > maxTechStack {
+		briefing.TechStack = briefing.TechStack[:maxTechStack]
+	}
+	briefing.Locations = locations.sorted()
+	briefing.WorkModes = workModes.sorted()
+	briefing.EmploymentTypes = employmentTypes.sorted()
+	briefing.Seniority = seniority.sorted()
+
+	statuses := newCounter()
+	briefing.Applications = append([]models.Application(nil), applications...)
+	sort.SliceStable(briefing.Applications, func(i, j int) bool {
+		return briefing.Applications[i].AppliedDate > briefing.Applications[j].AppliedDate
+	})
+	for _, application := range briefing.Applications {
+		statuses.add(application.Status)
+	}
+	briefing.Statuses = statuses.sorted()
+
+	return briefing
+}
+
+// counter tallies values case-insensitively, keeping the first spelling
+type counter struct {
+	names  map[string]string
+	counts map[string]int
+}
+
+func newCounter() *counter {
+	return &counter{names: map[string]string{}, counts: map[string]int{}}
+}
+
+func (c *counter) add(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	key := strings.ToLower(name)
+	if _, ok := c.names[key]; !ok {
+		c.names[key] = name
+	}
+	c.counts[key]++
+}
+
+// sorted returns the counts, most common first and then by name
+func (c *counter) sorted() []Count {
+	counts := make([]Count, 0, len(c.counts))
+	for key, count := range c.counts {
+		counts = append(counts, Count{Name: c.names[key], Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return strings.ToLower(counts[i].Name) < strings.ToLower(counts[j].Name)
+	})
+	return counts
+}
+
+var (
+	salaryFigure = regexp.MustCompile(`\d[\d,]*`)
+	// The scrapers only keep KSh salaries, but postings added by hand may
+	// state any currency or period
+	shillings   = regexp.MustCompile(`(?i)\b(ksh|kes)`)
+	otherPeriod = regexp.MustCompile(`(?i)\b(per|a|an|every)\s+(year|annum|week|day|hour)\b|/\s*(yr|year|week|day|hr|hour)\b|\b(yearly|annual|annually|weekly|daily|hourly)\b`)
+)
+
+// salaryBounds reads the lowest and highest figures from a monthly KSh
+// salary such as "Ksh 80,000 - Ksh 120,000"
+func salaryBounds(salary string) (int, int, bool) {
+	if !shillings.MatchString(salary) || otherPeriod.MatchString(salary) {
+		return 0, 0, false
+	}
+	low, high := 0, 0
+	for _, match := range salaryFigure.FindAllString(salary, -1) {
+		n, err := strconv.Atoi(strings.ReplaceAll(match, ",", ""))
+		if err != nil || n < minSalaryAmount {
+			continue
+		}
+		if low == 0 || n < low {
+			low = n
+		}
+		if n > high {
+			high = n
+		}
+	}
+	return low, high, high > 0
+}
+
+// formatAmount writes n with thousands separators
+func formatAmount(n int) string {
+	digits := strconv.Itoa(n)
+	var out strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out.WriteByte(',')
+		}
+		out.WriteRune(digit)
+	}
+	return out.String()
+}
+
+func stringList(data []byte) []string {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil
+	}
+	return list
+}
@@ -0,0 +1,173 @@
+package briefing
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"gorm.io/datatypes"
+)
+
+func TestSalaryBounds(t *testing.T) {
+	tests := []struct {
+		salary    string
+		low, high int
+		ok        bool
+	}{
+		{"Ksh 80,000 - Ksh 120,000", 80000, 120000, true},
+		{"Ksh 150,000 - 90,000 Ksh", 90000, 150000, true},
+		{"KSh150000 per month", 150000, 150000, true},
+		{"KES 60,000 - 75,000 pm", 60000, 75000, true},
+		{"Ksh 100,000 - 200,000, 3 years experience", 100000, 200000, true},
+		{"Negotiable", 0, 0, false},
+		{"", 0, 0, false},
+		{"Ksh 500", 0, 0, false},
+		{"USD 4,000 - 5,000", 0, 0, false},
+		{"$60,000", 0, 0, false},
+		{"Ksh 1,200,000 per year", 0, 0, false},
+		{"Ksh 2,500 a day", 0, 0, false},
+		{"KSh 3,000/hr", 0, 0, false},
+		{"Ksh 900,000 annually", 0, 0, false},
+	}
+	for _, tt := range tests {
+		low, high, ok := salaryBounds(tt.salary)
+		if low != tt.low || high != tt.high || ok != tt.ok {
+			t.Errorf("salaryBounds(%q) = %d, %d, %v, want %d, %d, %v", tt.salary, low, high, ok, tt.low, tt.high, tt.ok)
+		}
+	}
+}
+
+func TestSalaryText(t *testing.T) {
+	tests := []struct {
+		salary     Salary
+		rangeText  string
+		medianText string
+	}{
+		{Salary{}, "", ""},
+		{Salary{Stated: 1, Min: 90000, Max: 90000, Median: 90000}, "KSh 90,000", "KSh 90,000"},
+		{Salary{Stated: 3, Min: 80000, Max: 1250000, Median: 100000}, "KSh 80,000 to 1,250,000", "KSh 100,000"},
+	}
+	for _, tt := range tests {
+		if got := tt.salary.Range(); got != tt.rangeText {
+			t.Errorf("%+v.Range() = %q, want %q", tt.salary, got, tt.rangeText)
+		}
+		if got := tt.salary.MedianText(); got != tt.medianText {
+			t.Errorf("%+v.MedianText() = %q, want %q", tt.salary, got, tt.medianText)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	jobs := []models.Job{
+		{
+			ID: "j1", Title: "SOC Analyst", Location: "Nairobi", PostedDate: "2026-08-01",
+			ApplicationDeadline: "2026-08-30", SalaryRange: "Ksh 80,000 - Ksh 120,000", Score: 70,
+			TechStack: datatypes.JSON(`["Splunk","Linux"]`), Skills: datatypes.JSON(`["splunk","SIEM"]`),
+			WorkMode: "Hybrid", EmploymentType: "Full-time", Experience: "Mid",
+		},
+		{
+			ID: "j2", Title: "Security Engineer", Location: "nairobi", PostedDate: "2026-10-01",
+			SalaryRange: "Ksh 150,000 - Ksh 250,000", Score: 90,
+			TechStack: datatypes.JSON(`["Splunk","AWS"]`),
+			WorkMode:  "Remote", EmploymentType: "Full-time", Experience: "Senior",
+		},
+		{
+			ID: "j3", Title: "Network Engineer", Location: "Mombasa", PostedDate: "2026-09-10",
+			ApplicationDeadline: "2026-10-18", SalaryRange: "USD 3,000 - 4,000", Score: 50,
+			TechStack: datatypes.JSON(`not json`),
+			WorkMode:  "On-site", EmploymentType: "Contract", Experience: "Not specified",
+		},
+		{
+			ID: "j4", Title: "Intern", Location: "Nairobi", SalaryRange: "Ksh 30,000 per month", Score: 10,
+			EmploymentType: "Internship",
+		},
+	}
+	applications := []models.Application{
+		{ID: "a1", Role: "SOC Analyst", AppliedDate: "2026-08-05", Status: "Rejected"},
+		{ID: "a2", Role: "Security Engineer", AppliedDate: "2026-10-03", Status: "Interviewing"},
+		{ID: "a3", Role: "SOC Lead", AppliedDate: "2025-01-10", Status: "rejected"},
+	}
+
+	got := Build("Safaricom", jobs, applications, "  Panel of three  \n", now)
+
+	if got.Company != "Safaricom" || got.Notes != "Panel of three" {
+		t.Errorf("Company, Notes = %q, %q", got.Company, got.Notes)
+	}
+
+	var postings []string
+	for _, posting := range got.Postings {
+		postings = append(postings, posting.ID)
+	}
+	if want := []string{"j2", "j3", "j1", "j4"}; !reflect.DeepEqual(postings, want) {
+		t.Errorf("postings = %v, want newest first %v", postings, want)
+	}
+	// j1's deadline has passed; j3 closes today and j2, j4 state no deadline
+	if got.OpenPostings != 3 {
+		t.Errorf("OpenPostings = %d, want 3", got.OpenPostings)
+	}
+	if got.FirstPosted != "2026-08-01" || got.LastPosted != "2026-10-01" {
+		t.Errorf("posted %s to %s, want 2026-08-01 to 2026-10-01", got.FirstPosted, got.LastPosted)
+	}
+	if got.AverageScore != 55 {
+		t.Errorf("AverageScore = %d, want 55", got.AverageScore)
+	}
+
+	wantTech := []Count{{"Splunk", 2}, {"AWS", 1}, {"Linux", 1}, {"SIEM", 1}}
+	if !reflect.DeepEqual(got.TechStack, wantTech) {
+		t.Errorf("TechStack = %v, want %v", got.TechStack, wantTech)
+	}
+	if want := []Count{{"Nairobi", 3}, {"Mombasa", 1}}; !reflect.DeepEqual(got.Locations, want) {
+		t.Errorf("Locations = %v, want %v", got.Locations, want)
+	}
+	if want := []Count{{"Hybrid", 1}, {"On-site", 1}, {"Remote", 1}}; !reflect.DeepEqual(got.WorkModes, want) {
+		t.Errorf("WorkModes = %v, want %v", got.WorkModes, want)
+	}
+	if want := []Count{{"Full-time", 2}, {"Contract", 1}, {"Internship", 1}}; !reflect.DeepEqual(got.EmploymentTypes, want) {
+		t.Errorf("EmploymentTypes = %v, want %v", got.EmploymentTypes, want)
+	}
+	if want := []Count{{"Mid", 1}, {"Senior", 1}}; !reflect.DeepEqual(got.Seniority, want) {
+		t.Errorf("Seniority = %v, want %v", got.Seniority, want)
+	}
+
+	// The USD posting is left out; midpoints are 30,000, 100,000 and 200,000
+	wantSalary := Salary{Stated: 3, Min: 30000, Max: 250000, Median: 100000}
+	if got.Salary != wantSalary {
+		t.Errorf("Salary = %+v, want %+v", got.Salary, wantSalary)
+	}
+
+	var applied []string
+	for _, application := range got.Applications {
+		applied = append(applied, application.ID)
+	}
+	if want := []string{"a2", "a1", "a3"}; !reflect.DeepEqual(applied, want) {
+		t.Errorf("applications = %v, want newest first %v", applied, want)
+	}
+	if applications[0].ID != "a1" {
+		t.Errorf("Build reordered the caller's applications")
+	}
+	if want := []Count{{"Rejected", 2}, {"Interviewing", 1}}; !reflect.DeepEqual(got.Statuses, want) {
+		t.Errorf("Statuses = %v, want %v", got.Statuses, want)
+	}
+}
+
+func TestBuildMedianOfEvenCount(t *testing.T) {
+	jobs := []models.Job{
+		{SalaryRange: "Ksh 100,000"},
+		{SalaryRange: "Ksh 200,000"},
+		{SalaryRange: "Ksh 50,000"},
+		{SalaryRange: "Ksh 1,000,000"},
+	}
+	got := Build("Acme", jobs, nil, "", time.Now())
+	if got.Salary.Median != 150000 {
+		t.Errorf("Median = %d, want the middle pair's average 150000", got.Salary.Median)
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	got := Build("Acme", nil, nil, "", time.Now())
+	if got.AverageScore != 0 || got.Salary.Stated != 0 || len(got.Postings) != 0 || got.Salary.Range() != "" {
+		t.Errorf("Build with nothing = %+v", got)
+	}
+}
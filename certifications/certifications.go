@@ -0,0 +1,110 @@
+package certifications
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// Certification is a recognized industry credential
+type Certification struct {
+	Name    string
+	Issuer  string
+	pattern *regexp.Regexp
+}
+
+// ExpiringWindow is how far ahead an expiry date counts as "expiring"
+const ExpiringWindow = 90 * 24 * time.Hour
+
+// Match statuses reported in a skills analysis
+const (
+	StatusMet      = "met"
+	StatusExpiring = "expiring"
+	StatusUnmet    = "unmet"
+)
+
+// Catalog lists the certifications detected in postings. Patterns run
+// against lowercased text.
+var Catalog = []Certification{
+	{"CISSP", "ISC2", regexp.MustCompile(`\bcissp\b`)},
+	{"CCSP", "ISC2", regexp.MustCompile(`\bccsp\b`)},
+	{"CISM", "ISACA", regexp.MustCompile(`\bcism\b|certified information security manager`)},
+	{"CISA", "ISACA", regexp.MustCompile(`\bcisa\b|certified information systems auditor`)},
+	{"CRISC", "ISACA", regexp.MustCompile(`\bcrisc\b`)},
+	{"OSCP", "OffSec", regexp.MustCompile(`\boscp\b|offensive security certified professional`)},
+	{"CEH", "EC-Council", regexp.MustCompile(`\bceh\b|certified ethical hacker`)},
+	{"CompTIA Security+", "CompTIA", regexp.MustCompile(`security\+|\bsec\+`)},
+	{"CompTIA CySA+", "CompTIA", regexp.MustCompile(`cysa\+?`)},
+	{"GSEC", "GIAC", regexp.MustCompile(`\bgsec\b`)},
+	{"GCIH", "GIAC", regexp.MustCompile(`\bgcih\b`)},
+	{"GSOC", "GIAC", regexp.MustCompile(`\bgsoc\b`)},
+	{"AWS Security Specialty", "Amazon Web Services", regexp.MustCompile(`aws (certified )?security\s*[-–]?\s*specialty|aws security specialist certification`)},
+	{"Azure Security Engineer (AZ-500)", "Microsoft", regexp.MustCompile(`az-?500|azure security engineer`)},
+	{"CCNA", "Cisco", regexp.MustCompile(`\bccna\b`)},
+	{"CCNP Security", "Cisco", regexp.MustCompile(`ccnp security`)},
+	{"Fortinet NSE", "Fortinet", regexp.MustCompile(`\bnse ?[4-8]\b|fortinet certified`)},
+	{"PCNSE", "Palo Alto Networks", regexp.MustCompile(`\bpcnse\b`)},
+	{"ISO 27001 Lead Auditor", "PECB", regexp.MustCompile(`iso ?/?(iec )?27001 lead auditor`)},
+	{"ISO 27001 Lead Implementer", "PECB", regexp.MustCompile(`iso ?/?(iec )?27001 lead implementer`)},
+}
+
+// Extract returns the catalog names of every certification mentioned in text
+func Extract(text string) []string {
+	text = strings.ToLower(text)
+	found := []string{}
+	for _, cert := range Catalog {
+		if cert.pattern.MatchString(text) {
+			found = append(found, cert.Name)
+		}
+	}
+	return found
+}
+
+// Canonical maps user input such as "Security+" or "cissp" to its catalog
+// name, returning the trimmed input unchanged when it is not recognized.
+func Canonical(name string) string {
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+	for _, cert := range Catalog {
+		if strings.EqualFold(cert.Name, name) || cert.pattern.MatchString(lower) {
+			return cert.Name
+		}
+	}
+	return name
+}
+
+// MatchRequirements reports, for each certification a job asks for, whether
+// the user holds it, holds it but it expires soon, or lacks it (including
+// when their copy has already expired).
+func MatchRequirements(required []string, held []models.UserCertification, now time.Time) []models.CertificationMatch {
+	heldByName := make(map[string]models.UserCertification)
+	for _, cert := range held {
+		heldByName[strings.ToLower(Canonical(cert.Name))] = cert
+	}
+
+	matches := make([]models.CertificationMatch, 0, len(required))
+	for _, name := range required {
+		match := models.CertificationMatch{Name: name, Status: StatusUnmet}
+
+		if cert, ok := heldByName[strings.ToLower(name)]; ok {
+			match.ExpiryDate = cert.ExpiryDate
+			expiry, err := time.Parse("2006-01-02", cert.ExpiryDate)
+			switch {
+			case cert.ExpiryDate == "" || err != nil:
+				match.Status = StatusMet
+			case expiry.Before(now):
+				match.Detail = "Expired on " + cert.ExpiryDate
+			case expiry.Before(now.Add(ExpiringWindow)):
+				match.Status = StatusExpiring
+				match.Detail = "Expires on " + cert.ExpiryDate
+			default:
+				match.Status = StatusMet
+			}
+		}
+
+		matches = append(matches, match)
+	}
+	return matches
+}
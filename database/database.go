@@ -2,8 +2,10 @@ package database
 
 import (
 
+    "encoding/json"
     "fmt"
     "log"
+    "strings"
     "time"
 
     "gorm.io/driver/sqlite"
@@ -33,11 +35,27 @@ func InitDB() (*DB, error) {
         &models.Job{},
         &models.Application{},
         &models.UserSkill{},
+        &models.UserCertification{},
+        &models.ScoringProfile{},
+        &models.ExclusionRule{},
+        &models.JobFeedback{},
+        &models.Embedding{},
+        &models.CoverLetter{},
+        &models.CoverLetterVersion{},
+        &models.PromptOverride{},
+        &models.UserProfile{},
+        &models.Resume{},
+        &models.TailoredResume{},
+        &models.CompanyNote{},
     )
     if err != nil {
         return nil, fmt.Errorf("failed to auto migrate: %v", err)
     }
 
+    if err := (&DB{db}).migrateUserProfile(); err != nil {
+        return nil, fmt.Errorf("failed to create user profile: %v", err)
+    }
+
     // Create default skills
     defaultSkills := []string{
         "AWS", "Python", "Go", "Fortinet", "SIEM", "Docker", 
@@ -70,23 +88,49 @@ func (db *DB) SaveJob(job *models.Job) error {
     if job.TechStack == nil {
         job.TechStack = datatypes.JSON([]byte(`[]`))
     }
+    if job.Sections == nil {
+        job.Sections = datatypes.JSON([]byte(`[]`))
+    }
+    if job.Certifications == nil {
+        job.Certifications = datatypes.JSON([]byte(`[]`))
+    }
+    if job.ScoreBreakdown == nil {
+        job.ScoreBreakdown = datatypes.JSON([]byte(`{}`))
+    }
+    if job.FilterReasons == nil {
+        job.FilterReasons = datatypes.JSON([]byte(`[]`))
+    }
 
     // Use GORM's Create with conflict handling
     result := db.Clauses(
         clause.OnConflict{
             Columns:   []clause.Column{{Name: "url"}},
             DoUpdates: clause.Assignments(map[string]interface{}{
-                "title":        job.Title,
-                "company":      job.Company,
-                "location":     job.Location,
-                "description":  job.Description,
-                "salary_range": job.SalaryRange,
-                "experience":   job.Experience,
-                "posted_date":  job.PostedDate,
-                "source":       job.Source,
-                "score":        job.Score,
-                "skills":       job.Skills,
-                "tech_stack":   job.TechStack,
+                "title":                job.Title,
+                "company":              job.Company,
+                "location":             job.Location,
+                "description":          job.Description,
+                "description_html":     job.DescriptionHTML,
+                "salary_range":         job.SalaryRange,
+                "experience":           job.Experience,
+                "posted_date":          job.PostedDate,
+                "source":               job.Source,
+                "score":                job.Score,
+                "skills":               job.Skills,
+                "tech_stack":           job.TechStack,
+                "employment_type":      job.EmploymentType,
+                "work_mode":            job.WorkMode,
+                "application_deadline": job.ApplicationDeadline,
+                "sections":             job.Sections,
+                "certifications":       job.Certifications,
+                "score_breakdown":      job.ScoreBreakdown,
+                "filter_action":        job.FilterAction,
+                "filter_reasons":       job.FilterReasons,
+                "city":                 job.City,
+                "region":               job.Region,
+                "country":              job.Country,
+                "latitude":             job.Latitude,
+                "longitude":            job.Longitude,
             }),
         },
     ).Create(job)
@@ -99,7 +143,11 @@ func (db *DB) SaveJob(job *models.Job) error {
 
 func (db *DB) GetJobs(limit, offset int) ([]models.Job, error) {
     var jobs []models.Job
-    result := db.Order("score DESC, posted_date DESC").
+    // Jobs dropped by an exclusion rule only appear in GetFilteredJobs, and
+    // dismissed jobs are hidden
+    result := db.Where("filter_action <> ?", "dropped").
+        Where("id NOT IN (?)", db.Model(&models.JobFeedback{}).Select("job_id").Where("signal = ?", "dismissed")).
+        Order("score DESC, posted_date DESC").
         Limit(limit).
         Offset(offset).
         Find(&jobs)
@@ -121,6 +169,39 @@ func (db *DB) GetJobs(limit, offset int) ([]models.Job, error) {
     return jobs, nil
 }
 
+// GetJobsAfter returns up to limit jobs with IDs after afterID, in ID order,
+// for walking the whole table in batches
+func (db *DB) GetJobsAfter(afterID string, limit int) ([]models.Job, error) {
+    var jobs []models.Job
+    result := db.Where("id > ?", afterID).Order("id").Limit(limit).Find(&jobs)
+    return jobs, result.Error
+}
+
+func (db *DB) CountJobs() (int, error) {
+    var count int64
+    result := db.Model(&models.Job{}).Count(&count)
+    return int(count), result.Error
+}
+
+// UpdateJobScore stores a recomputed score and exclusion result without
+// touching the other columns
+func (db *DB) UpdateJobScore(job *models.Job) error {
+    result := db.Model(&models.Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+        "score":           job.Score,
+        "score_breakdown": job.ScoreBreakdown,
+        "filter_action":   job.FilterAction,
+        "filter_reasons":  job.FilterReasons,
+    })
+    return result.Error
+}
+
+// GetFilteredJobs returns the jobs dropped or demoted by exclusion rules
+func (db *DB) GetFilteredJobs() ([]models.Job, error) {
+    var jobs []models.Job
+    result := db.Where("filter_action <> ?", "").Order("filter_action DESC, created_at DESC").Find(&jobs)
+    return jobs, result.Error
+}
+
 func (db *DB) GetJobByID(id string) (*models.Job, error) {
     var job models.Job
     result := db.First(&job, "id = ?", id)
@@ -150,6 +231,26 @@ func (db *DB) UpdateApplicationStatus(id, status string) error {
     return result.Error
 }
 
+func (db *DB) GetApplication(id string) (*models.Application, error) {
+    var application models.Application
+    result := db.First(&application, "id = ?", id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &application, nil
+}
+
+// SaveInterviewPrep stores an application's interview prep pack, replacing
+// any earlier one
+func (db *DB) SaveInterviewPrep(id, content, model string) error {
+    now := time.Now()
+    return db.Model(&models.Application{}).Where("id = ?", id).Updates(map[string]interface{}{
+        "prep_pack":    content,
+        "prep_model":   model,
+        "prep_updated": &now,
+    }).Error
+}
+
 func (db *DB) GetUserSkills() ([]string, error) {
     var userSkills []models.UserSkill
     result := db.Find(&userSkills)
@@ -171,16 +272,379 @@ func (db *DB) AddUserSkill(skill string) error {
     return result.Error
 }
 
+func (db *DB) GetUserCertifications() ([]models.UserCertification, error) {
+    var certs []models.UserCertification
+    result := db.Order("name ASC").Find(&certs)
+    return certs, result.Error
+}
+
+func (db *DB) SaveUserCertification(cert *models.UserCertification) error {
+    // Re-adding a certification updates its dates (e.g. after renewal)
+    result := db.Clauses(
+        clause.OnConflict{
+            Columns:   []clause.Column{{Name: "name"}},
+            DoUpdates: clause.Assignments(map[string]interface{}{
+                "credential_id": cert.CredentialID,
+                "issued_date":   cert.IssuedDate,
+                "expiry_date":   cert.ExpiryDate,
+            }),
+        },
+    ).Create(cert)
+    return result.Error
+}
+
+func (db *DB) DeleteUserCertification(id uint) error {
+    result := db.Delete(&models.UserCertification{}, id)
+    return result.Error
+}
+
+// GetScoringProfile returns the scoring profile, creating the default one on
+// first use
+func (db *DB) GetScoringProfile() (*models.ScoringProfile, error) {
+    profile := DefaultScoringProfile()
+    result := db.FirstOrCreate(profile, models.ScoringProfile{ID: profile.ID})
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return profile, nil
+}
+
+func (db *DB) SaveScoringProfile(profile *models.ScoringProfile) error {
+    // There is a single profile
+    profile.ID = 1
+    return db.Save(profile).Error
+}
+
+// DefaultScoringProfile reproduces the original fixed scoring: skills 60,
+// experience 20, salary 10 and company 10 points. Relevance and semantic
+// match only count once the user profile describes the user, feedback once
+// enough jobs have been rated.
+func DefaultScoringProfile() *models.ScoringProfile {
+    companies, _ := json.Marshal([]string{"safaricom", "kcb", "equity", "google", "microsoft", "amazon", "oracle", "ibm"})
+    return &models.ScoringProfile{
+        ID:                 1,
+        SkillsWeight:       60,
+        ExperienceWeight:   20,
+        SalaryWeight:       10,
+        CompanyWeight:      10,
+        RelevanceWeight:    20,
+        FeedbackWeight:     15,
+        SemanticWeight:     15,
+        PreferredCompanies: datatypes.JSON(companies),
+        SeniorityTarget:    "any",
+    }
+}
+
+func (db *DB) GetUserProfile() (*models.UserProfile, error) {
+    profile := DefaultUserProfile()
+    result := db.FirstOrCreate(profile, models.UserProfile{ID: profile.ID})
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return profile, nil
+}
+
+func (db *DB) SaveUserProfile(profile *models.UserProfile) error {
+    // There is a single profile
+    profile.ID = 1
+    return db.Save(profile).Error
+}
+
+// DefaultUserProfile is an empty profile
+func DefaultUserProfile() *models.UserProfile {
+    return &models.UserProfile{
+        ID:              1,
+        WorkHistory:     datatypes.JSON([]byte(`[]`)),
+        Education:       datatypes.JSON([]byte(`[]`)),
+        TargetRoles:     datatypes.JSON([]byte(`[]`)),
+        TargetLocations: datatypes.JSON([]byte(`[]`)),
+    }
+}
+
+// migrateUserProfile creates the user profile the first time, carrying over
+// the experience, salary, locations and profile text that used to be kept
+// with the scoring settings
+func (db *DB) migrateUserProfile() error {
+    var count int64
+    if err := db.Model(&models.UserProfile{}).Count(&count).Error; err != nil || count > 0 {
+        return err
+    }
+
+    profile := DefaultUserProfile()
+    if db.Migrator().HasColumn(&models.ScoringProfile{}, "profile_text") {
+        var old struct {
+            PreferredLocations string
+            MinSalary          int
+            YearsExperience    int
+            ProfileText        string
+        }
+        result := db.Table("scoring_profiles").
+            Select("preferred_locations, min_salary, years_experience, profile_text").
+            Where("id = ?", 1).Limit(1).Scan(&old)
+        if result.Error != nil {
+            return result.Error
+        }
+        if old.PreferredLocations != "" {
+            profile.TargetLocations = datatypes.JSON([]byte(old.PreferredLocations))
+        }
+        profile.SalaryMin = old.MinSalary
+        profile.YearsExperience = old.YearsExperience
+        profile.Summary = old.ProfileText
+    }
+    return db.Create(profile).Error
+}
+
+func (db *DB) SaveResume(resume *models.Resume) error {
+    return db.Create(resume).Error
+}
+
+func (db *DB) GetResume(id uint) (*models.Resume, error) {
+    var resume models.Resume
+    result := db.First(&resume, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &resume, nil
+}
+
+// GetLatestResume returns the most recently uploaded resume, or nil if there
+// is none
+func (db *DB) GetLatestResume() (*models.Resume, error) {
+    var resumes []models.Resume
+    result := db.Omit("data").Order("created_at DESC, id DESC").Limit(1).Find(&resumes)
+    if result.Error != nil || len(resumes) == 0 {
+        return nil, result.Error
+    }
+    return &resumes[0], nil
+}
+
+// MarkResumeReviewed marks a resume as reviewed, reporting false if it was
+// already reviewed. The check and the update are one statement, so only one
+// of two concurrent reviews gets true.
+func (db *DB) MarkResumeReviewed(id uint) (bool, error) {
+    result := db.Model(&models.Resume{}).Where("id = ? AND reviewed_at IS NULL", id).Update("reviewed_at", time.Now())
+    return result.RowsAffected > 0, result.Error
+}
+
+func (db *DB) GetExclusionRules() ([]models.ExclusionRule, error) {
+    var rules []models.ExclusionRule
+    result := db.Order("created_at").Find(&rules)
+    return rules, result.Error
+}
+
+func (db *DB) SaveExclusionRule(rule *models.ExclusionRule) error {
+    return db.Create(rule).Error
+}
+
+func (db *DB) DeleteExclusionRule(id uint) error {
+    result := db.Delete(&models.ExclusionRule{}, id)
+    return result.Error
+}
+
+// GetJobFeedback returns the signal given to a job, or "" if none
+func (db *DB) GetJobFeedback(jobID string) (string, error) {
+    var feedback models.JobFeedback
+    result := db.Where("job_id = ?", jobID).Limit(1).Find(&feedback)
+    return feedback.Signal, result.Error
+}
+
+// SetJobFeedback records a signal for a job; an empty signal clears it
+func (db *DB) SetJobFeedback(jobID, signal string) error {
+    if signal == "" {
+        return db.Where("job_id = ?", jobID).Delete(&models.JobFeedback{}).Error
+    }
+    feedback := models.JobFeedback{JobID: jobID, Signal: signal}
+    return db.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "job_id"}},
+        DoUpdates: clause.AssignmentColumns([]string{"signal", "updated_at"}),
+    }).Create(&feedback).Error
+}
+
+// GetRatedJobs returns the jobs that were liked, dismissed or applied to,
+// with the signal for each. Applying counts as liking.
+func (db *DB) GetRatedJobs() ([]models.Job, []string, error) {
+    var feedback []models.JobFeedback
+    if err := db.Find(&feedback).Error; err != nil {
+        return nil, nil, err
+    }
+    signals := map[string]string{}
+    for _, f := range feedback {
+        signals[f.JobID] = f.Signal
+    }
+
+    var appliedIDs []string
+    if err := db.Model(&models.Application{}).Where("job_id <> ?", "").Pluck("job_id", &appliedIDs).Error; err != nil {
+        return nil, nil, err
+    }
+    for _, id := range appliedIDs {
+        if _, ok := signals[id]; !ok {
+            signals[id] = "liked"
+        }
+    }
+
+    ids := make([]string, 0, len(signals))
+    for id := range signals {
+        ids = append(ids, id)
+    }
+    var jobs []models.Job
+    if err := db.Where("id IN ?", ids).Order("id").Find(&jobs).Error; err != nil {
+        return nil, nil, err
+    }
+
+    labels := make([]string, len(jobs))
+    for i, job := range jobs {
+        labels[i] = signals[job.ID]
+    }
+    return jobs, labels, nil
+}
+
+// GetEmbeddings returns the stored vectors for the given hashes, by hash
+func (db *DB) GetEmbeddings(hashes []string) (map[string][]byte, error) {
+    var embeddings []models.Embedding
+    result := db.Where("hash IN ?", hashes).Find(&embeddings)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+
+    vectors := make(map[string][]byte, len(embeddings))
+    for _, embedding := range embeddings {
+        vectors[embedding.Hash] = embedding.Vector
+    }
+    return vectors, nil
+}
+
+func (db *DB) SaveEmbeddings(embeddings []models.Embedding) error {
+    if len(embeddings) == 0 {
+        return nil
+    }
+    return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&embeddings).Error
+}
+
+// AddCoverLetterVersion saves version as the latest revision of letter,
+// creating the letter first if it is new
+func (db *DB) AddCoverLetterVersion(letter *models.CoverLetter, version *models.CoverLetterVersion) error {
+    return db.Transaction(func(tx *gorm.DB) error {
+        letter.Content = version.Content
+        letter.Model = version.Model
+        if err := tx.Save(letter).Error; err != nil {
+            return err
+        }
+
+        var count int64
+        if err := tx.Model(&models.CoverLetterVersion{}).Where("cover_letter_id = ?", letter.ID).Count(&count).Error; err != nil {
+            return err
+        }
+        version.CoverLetterID = letter.ID
+        version.Version = int(count) + 1
+        return tx.Create(version).Error
+    })
+}
+
+// GetCoverLetter returns a letter with its versions, newest first
+func (db *DB) GetCoverLetter(id uint) (*models.CoverLetter, error) {
+    var letter models.CoverLetter
+    result := db.Preload("Versions", func(tx *gorm.DB) *gorm.DB {
+        return tx.Order("version DESC")
+    }).First(&letter, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &letter, nil
+}
+
+// GetLatestCoverLetter returns the most recently changed letter for a job,
+// without its versions, or nil if there is none
+func (db *DB) GetLatestCoverLetter(jobID string) (*models.CoverLetter, error) {
+    var letters []models.CoverLetter
+    result := db.Where("job_id = ?", jobID).Order("updated_at DESC").Limit(1).Find(&letters)
+    if result.Error != nil || len(letters) == 0 {
+        return nil, result.Error
+    }
+    return &letters[0], nil
+}
+
+// AttachCoverLetter links a job's letter to an application, replacing any
+// letter attached before. It returns gorm.ErrRecordNotFound if the job has
+// no letter with that ID.
+func (db *DB) AttachCoverLetter(id uint, jobID, applicationID string) error {
+    return db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(&models.CoverLetter{}).Where("application_id = ?", applicationID).
+            Update("application_id", "").Error; err != nil {
+            return err
+        }
+        result := tx.Model(&models.CoverLetter{}).Where("id = ? AND job_id = ?", id, jobID).Update("application_id", applicationID)
+        if result.Error == nil && result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return result.Error
+    })
+}
+
+func (db *DB) SaveTailoredResume(resume *models.TailoredResume) error {
+    return db.Create(resume).Error
+}
+
+func (db *DB) GetTailoredResume(id uint) (*models.TailoredResume, error) {
+    var resume models.TailoredResume
+    result := db.First(&resume, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &resume, nil
+}
+
+// GetTailoredResumes lists a job's tailored resumes, newest first
+func (db *DB) GetTailoredResumes(jobID string) ([]models.TailoredResume, error) {
+    var resumes []models.TailoredResume
+    result := db.Where("job_id = ?", jobID).Order("created_at DESC, id DESC").Find(&resumes)
+    return resumes, result.Error
+}
+
+// AttachTailoredResume links a job's tailored resume to an application,
+// replacing any resume attached before. It returns gorm.ErrRecordNotFound if
+// the job has no resume with that ID.
+func (db *DB) AttachTailoredResume(id uint, jobID, applicationID string) error {
+    return db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(&models.TailoredResume{}).Where("application_id = ?", applicationID).
+            Update("application_id", "").Error; err != nil {
+            return err
+        }
+        result := tx.Model(&models.TailoredResume{}).Where("id = ? AND job_id = ?", id, jobID).Update("application_id", applicationID)
+        if result.Error == nil && result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return result.Error
+    })
+}
+
+func (db *DB) GetPromptOverrides() ([]models.PromptOverride, error) {
+    var overrides []models.PromptOverride
+    result := db.Find(&overrides)
+    return overrides, result.Error
+}
+
+// SetPromptOverride saves the user's template; an empty body deletes it
+func (db *DB) SetPromptOverride(name, body string) error {
+    if body == "" {
+        return db.Delete(&models.PromptOverride{}, "name = ?", name).Error
+    }
+    override := models.PromptOverride{Name: name, Body: body}
+    return db.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "name"}},
+        DoUpdates: clause.AssignmentColumns([]string{"body", "updated_at"}),
+    }).Create(&override).Error
+}
+
 func (db *DB) GetJobStats() (totalJobs, highScoreJobs int, err error) {
     var total int64
-    result := db.Model(&models.Job{}).Count(&total)
+    result := db.Model(&models.Job{}).Where("filter_action <> ?", "dropped").Count(&total)
     if result.Error != nil {
         return 0, 0, result.Error
     }
     totalJobs = int(total)
 
     var highScore int64
-    result = db.Model(&models.Job{}).Where("score >= ?", 80).Count(&highScore)
+    result = db.Model(&models.Job{}).Where("score >= ? AND filter_action <> ?", 80, "dropped").Count(&highScore)
     if result.Error != nil {
         return 0, 0, result.Error
     }
@@ -195,12 +659,63 @@ func (db *DB) GetApplicationStats() (int, error) {
     return int(count), result.Error
 }
 
+// GetJobsByCompany returns a company's postings, matched without regard to
+// case or surrounding spaces. Like GetJobs, it leaves out dropped and
+// dismissed jobs.
 func (db *DB) GetJobsByCompany(companyName string) ([]models.Job, error) {
     var jobs []models.Job
-    result := db.Where("company = ?", companyName).Find(&jobs)
+    result := db.Where("LOWER(TRIM(company)) = LOWER(TRIM(?))", companyName).
+        Where("filter_action <> ?", "dropped").
+        Where("id NOT IN (?)", db.Model(&models.JobFeedback{}).Select("job_id").Where("signal = ?", "dismissed")).
+        Find(&jobs)
     return jobs, result.Error
 }
 
+// GetApplicationsByCompany returns the applications to a company, matched
+// without regard to case or surrounding spaces
+func (db *DB) GetApplicationsByCompany(companyName string) ([]models.Application, error) {
+    var applications []models.Application
+    result := db.Where("LOWER(TRIM(company)) = LOWER(TRIM(?))", companyName).Order("applied_date DESC").Find(&applications)
+    return applications, result.Error
+}
+
+// companyKey is how a company's notes are keyed, so "Safaricom" and
+// "safaricom " share them the way their postings and applications do
+func companyKey(companyName string) string {
+    return strings.ToLower(strings.TrimSpace(companyName))
+}
+
+// GetCompanyNote returns the user's notes and summary for a company, empty
+// when there are none yet
+func (db *DB) GetCompanyNote(companyName string) (*models.CompanyNote, error) {
+    var notes []models.CompanyNote
+    result := db.Where("company = ?", companyKey(companyName)).Limit(1).Find(&notes)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if len(notes) == 0 {
+        return &models.CompanyNote{Company: companyKey(companyName)}, nil
+    }
+    return &notes[0], nil
+}
+
+func (db *DB) SaveCompanyNotes(companyName, notes string) error {
+    note := models.CompanyNote{Company: companyKey(companyName), Notes: notes}
+    return db.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "company"}},
+        DoUpdates: clause.AssignmentColumns([]string{"notes", "updated_at"}),
+    }).Create(&note).Error
+}
+
+func (db *DB) SaveCompanySummary(companyName, summary, model string) error {
+    now := time.Now()
+    note := models.CompanyNote{Company: companyKey(companyName), Summary: summary, SummaryModel: model, SummarizedAt: &now}
+    return db.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "company"}},
+        DoUpdates: clause.AssignmentColumns([]string{"summary", "summary_model", "summarized_at", "updated_at"}),
+    }).Create(&note).Error
+}
+
 func (db *DB) Close() error {
     sqlDB, err := db.DB.DB()
     if err != nil {
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	db, err := InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMarkResumeReviewedOnce(t *testing.T) {
+	db := newTestDB(t)
+	upload := models.Resume{Filename: "resume.pdf"}
+	if err := db.SaveResume(&upload); err != nil {
+		t.Fatal(err)
+	}
+
+	// Of several submits racing to review, exactly one wins
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			marked, err := db.MarkResumeReviewed(upload.ID)
+			if err != nil {
+				t.Error(err)
+			}
+			if marked {
+				wins.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if wins.Load() != 1 {
+		t.Errorf("%d reviews were applied, want 1", wins.Load())
+	}
+
+	if marked, err := db.MarkResumeReviewed(upload.ID + 1); err != nil || marked {
+		t.Errorf("MarkResumeReviewed(missing) = %v, %v", marked, err)
+	}
+}
+
+func TestGetJobsByCompany(t *testing.T) {
+	db := newTestDB(t)
+	for _, job := range []models.Job{
+		{ID: "1", URL: "https://jobs.example/1", Title: "SOC Analyst", Company: "Safaricom"},
+		{ID: "2", URL: "https://jobs.example/2", Title: "Network Engineer", Company: " safaricom "},
+		{ID: "3", URL: "https://jobs.example/3", Title: "Sales Lead", Company: "SAFARICOM", FilterAction: "dropped"},
+		{ID: "4", URL: "https://jobs.example/4", Title: "Intern", Company: "Safaricom"},
+		{ID: "5", URL: "https://jobs.example/5", Title: "SOC Analyst", Company: "Safaricom Foundation"},
+	} {
+		if err := db.SaveJob(&job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SetJobFeedback("4", "dismissed"); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := db.GetJobsByCompany("SafariCom ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	if strings.Join(ids, ",") != "1,2" {
+		t.Errorf("GetJobsByCompany = %v, want jobs 1 and 2", ids)
+	}
+}
@@ -0,0 +1,104 @@
+package document
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTMLFragment renders Markdown as HTML for embedding in a page. All text
+// is escaped, and links are kept only for http, https and mailto URLs.
+func HTMLFragment(markdown string) string {
+	var out strings.Builder
+	for _, block := range Parse(markdown) {
+		switch block.Kind {
+		case Heading:
+			fmt.Fprintf(&out, "<h%d>%s</h%d>\n", block.Level, inlineHTML(block.Text), block.Level)
+		case Paragraph:
+			lines := strings.Split(block.Text, "\n")
+			for i, line := range lines {
+				lines[i] = inlineHTML(line)
+			}
+			fmt.Fprintf(&out, "<p>%s</p>\n", strings.Join(lines, "<br>\n"))
+		case Rule:
+			out.WriteString("<hr>\n")
+		case List:
+			writeListHTML(&out, block.Items)
+		}
+	}
+	return out.String()
+}
+
+// HTML renders Markdown as a standalone page that prints cleanly
+func HTML(title, markdown string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 11pt; line-height: 1.45; color: #111827; max-width: 48rem; margin: 2rem auto; padding: 0 1.5rem; }
+h1 { font-size: 22pt; margin: 0 0 0.25rem; }
+h2 { font-size: 13pt; text-transform: uppercase; letter-spacing: 0.05em; border-bottom: 1px solid #d1d5db; padding-bottom: 0.2rem; margin: 1.5rem 0 0.5rem; }
+h3 { font-size: 11.5pt; margin: 1rem 0 0.25rem; }
+p { margin: 0.25rem 0 0.5rem; }
+ul { margin: 0.25rem 0 0.5rem; padding-left: 1.25rem; }
+a { color: inherit; }
+@media print { body { margin: 0; max-width: none; } }
+</style>
+</head>
+<body>
+%s</body>
+</html>
+`, html.EscapeString(title), HTMLFragment(markdown))
+}
+
+func writeListHTML(out *strings.Builder, items []Item) {
+	out.WriteString("<ul>\n")
+	nested := false
+	for i, item := range items {
+		if item.Depth > 0 && !nested {
+			out.WriteString("<ul>\n")
+			nested = true
+		} else if item.Depth == 0 && nested {
+			out.WriteString("</ul></li>\n")
+			nested = false
+		} else if i > 0 && !nested {
+			out.WriteString("</li>\n")
+		}
+		if item.Depth > 0 {
+			fmt.Fprintf(out, "<li>%s</li>\n", inlineHTML(item.Text))
+		} else {
+			fmt.Fprintf(out, "<li>%s", inlineHTML(item.Text))
+		}
+	}
+	if nested {
+		out.WriteString("</ul>")
+	}
+	out.WriteString("</li>\n</ul>\n")
+}
+
+func inlineHTML(text string) string {
+	var out strings.Builder
+	for _, span := range Spans(text) {
+		escaped := html.EscapeString(span.Text)
+		switch {
+		case span.Bold:
+			fmt.Fprintf(&out, "<strong>%s</strong>", escaped)
+		case span.Italic:
+			fmt.Fprintf(&out, "<em>%s</em>", escaped)
+		case span.Code:
+			fmt.Fprintf(&out, "<code>%s</code>", escaped)
+		case span.URL != "" && safeURL(span.URL):
+			fmt.Fprintf(&out, `<a href="%s">%s</a>`, html.EscapeString(span.URL), escaped)
+		default:
+			out.WriteString(escaped)
+		}
+	}
+	return out.String()
+}
+
+func safeURL(url string) bool {
+	lower := strings.ToLower(url)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
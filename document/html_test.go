@@ -0,0 +1,66 @@
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLFragment(t *testing.T) {
+	markdown := "# Jane Doe\n" +
+		"Nairobi\n" +
+		"jane@example.com\n" +
+		"\n" +
+		"- **Splunk** and *QRadar*\n" +
+		"  - `index=main`\n" +
+		"- [Portfolio](https://example.com)\n" +
+		"\n" +
+		"---\n"
+
+	want := "<h1>Jane Doe</h1>\n" +
+		"<p>Nairobi<br>\njane@example.com</p>\n" +
+		"<ul>\n" +
+		"<li><strong>Splunk</strong> and <em>QRadar</em><ul>\n" +
+		"<li><code>index=main</code></li>\n" +
+		"</ul></li>\n" +
+		"<li><a href=\"https://example.com\">Portfolio</a></li>\n" +
+		"</ul>\n" +
+		"<hr>\n"
+
+	if got := HTMLFragment(markdown); got != want {
+		t.Errorf("HTMLFragment =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHTMLFragmentEscapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"script in a paragraph", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"markup in a heading", "## R&D <b>", "<h2>R&amp;D &lt;b&gt;</h2>\n"},
+		{"markup in bold", "**<img src=x onerror=alert(1)>**", "<p><strong>&lt;img src=x onerror=alert(1)&gt;</strong></p>\n"},
+		{"markup in code", "`<br>`", "<p><code>&lt;br&gt;</code></p>\n"},
+		{"javascript link", "[click](javascript:void)", "<p>click</p>\n"},
+		{"data link", "[click](data:text/html,hi)", "<p>click</p>\n"},
+		{"quote in a link", `[site](https://example.com/"onmouseover="alert(1))`, `<p><a href="https://example.com/&#34;onmouseover=&#34;alert(1">site</a>)</p>` + "\n"},
+		{"mailto link", "[Email](mailto:jane@example.com)", "<p><a href=\"mailto:jane@example.com\">Email</a></p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLFragment(tt.markdown); got != tt.want {
+				t.Errorf("HTMLFragment(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLEscapesTitle(t *testing.T) {
+	page := HTML("Jane </title><script>", "Hello")
+	if !strings.Contains(page, "<title>Jane &lt;/title&gt;&lt;script&gt;</title>") {
+		t.Errorf("title not escaped:\n%s", page)
+	}
+	if !strings.Contains(page, "<body>\n<p>Hello</p>\n</body>") {
+		t.Errorf("body missing:\n%s", page)
+	}
+}
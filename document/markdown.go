@@ -0,0 +1,143 @@
+// Package document exports the Markdown the AI generators write as HTML and
+// PDF. It understands the subset those documents use: # headings,
+// paragraphs, bullet lists (one level of nesting), horizontal rules, and
+// **bold**, *italic*, `code` and [links](url) within text.
+package document
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kinds of block
+const (
+	Heading   = "heading"
+	Paragraph = "paragraph"
+	List      = "list"
+	Rule      = "rule"
+)
+
+// Block is a heading, paragraph, list or horizontal rule
+type Block struct {
+	Kind  string
+	Level int    // 1-6 for headings
+	Text  string // headings and paragraphs, with inline markup
+	Items []Item // lists
+}
+
+// Item is one bullet of a list
+type Item struct {
+	Text  string
+	Depth int // 0 for top-level bullets, 1 for nested ones
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)(?:[-*+•]|\d+[.)])\s+(.*)$`)
+	rulePattern    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+)
+
+// Parse splits Markdown into blocks. Lines of a paragraph keep their breaks,
+// since resumes put contact details and dates on lines of their own.
+func Parse(markdown string) []Block {
+	var blocks []Block
+	var paragraph []string
+	var list []Item
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, Block{Kind: Paragraph, Text: strings.Join(paragraph, "\n")})
+			paragraph = nil
+		}
+		if len(list) > 0 {
+			blocks = append(blocks, Block{Kind: List, Items: list})
+			list = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case rulePattern.MatchString(line):
+			flush()
+			blocks = append(blocks, Block{Kind: Rule})
+		case headingPattern.MatchString(trimmed):
+			flush()
+			match := headingPattern.FindStringSubmatch(trimmed)
+			blocks = append(blocks, Block{Kind: Heading, Level: len(match[1]), Text: match[2]})
+		case bulletPattern.MatchString(line):
+			if len(paragraph) > 0 {
+				flush()
+			}
+			match := bulletPattern.FindStringSubmatch(line)
+			depth := 0
+			if len(strings.ReplaceAll(match[1], "\t", "  ")) >= 2 && len(list) > 0 {
+				depth = 1
+			}
+			list = append(list, Item{Text: match[2], Depth: depth})
+		case len(list) > 0 && strings.HasPrefix(line, " "):
+			// A wrapped bullet continues the previous item
+			list[len(list)-1].Text += " " + trimmed
+		default:
+			if len(list) > 0 {
+				flush()
+			}
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// Span is a run of text with one style
+type Span struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Code   bool
+	URL    string
+}
+
+var inlinePattern = regexp.MustCompile("\\*\\*(.+?)\\*\\*|__(.+?)__|\\*(.+?)\\*|\\b_(.+?)_\\b|`(.+?)`|\\[(.+?)\\]\\((\\S+?)\\)")
+
+// Spans splits text with inline markup into styled runs
+func Spans(text string) []Span {
+	var spans []Span
+	last := 0
+	for _, m := range inlinePattern.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > last {
+			spans = append(spans, Span{Text: text[last:m[0]]})
+		}
+		group := func(n int) string { return text[m[2*n]:m[2*n+1]] }
+		switch {
+		case m[2] >= 0:
+			spans = append(spans, Span{Text: group(1), Bold: true})
+		case m[4] >= 0:
+			spans = append(spans, Span{Text: group(2), Bold: true})
+		case m[6] >= 0:
+			spans = append(spans, Span{Text: group(3), Italic: true})
+		case m[8] >= 0:
+			spans = append(spans, Span{Text: group(4), Italic: true})
+		case m[10] >= 0:
+			spans = append(spans, Span{Text: group(5), Code: true})
+		default:
+			spans = append(spans, Span{Text: group(6), URL: group(7)})
+		}
+		last = m[1]
+	}
+	if last < len(text) {
+		spans = append(spans, Span{Text: text[last:]})
+	}
+	return spans
+}
+
+// PlainText is text with its inline markup removed
+func PlainText(text string) string {
+	var out strings.Builder
+	for _, span := range Spans(text) {
+		out.WriteString(span.Text)
+	}
+	return out.String()
+}
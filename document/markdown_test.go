@@ -0,0 +1,95 @@
+package document
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	markdown := "# Jane Doe\r\n" +
+		"Nairobi | jane@example.com\n" +
+		"+254 700 000 000\n" +
+		"\n" +
+		"## Experience ##\n" +
+		"**SOC Analyst**, Safaricom\n" +
+		"- Triaged alerts in Splunk\n" +
+		"  - Cut false positives by a third\n" +
+		"- Wrote detection rules that ran\n" +
+		"  across every branch\n" +
+		"1. Numbered items are bullets too\n" +
+		"Back to a paragraph\n" +
+		"\n" +
+		"---\n" +
+		"####### Not a heading\n"
+
+	want := []Block{
+		{Kind: Heading, Level: 1, Text: "Jane Doe"},
+		{Kind: Paragraph, Text: "Nairobi | jane@example.com\n+254 700 000 000"},
+		{Kind: Heading, Level: 2, Text: "Experience"},
+		{Kind: Paragraph, Text: "**SOC Analyst**, Safaricom"},
+		{Kind: List, Items: []Item{
+			{Text: "Triaged alerts in Splunk"},
+			{Text: "Cut false positives by a third", Depth: 1},
+			{Text: "Wrote detection rules that ran across every branch"},
+			{Text: "Numbered items are bullets too"},
+		}},
+		{Kind: Paragraph, Text: "Back to a paragraph"},
+		{Kind: Rule},
+		{Kind: Paragraph, Text: "####### Not a heading"},
+	}
+
+	if got := Parse(markdown); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseNestedBulletNeedsParent(t *testing.T) {
+	got := Parse("  - indented first item\n- second")
+	want := []Block{{Kind: List, Items: []Item{{Text: "indented first item"}, {Text: "second"}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %+v, want %+v", got, want)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	tests := []struct {
+		text string
+		want []Span
+	}{
+		{"plain", []Span{{Text: "plain"}}},
+		{"", nil},
+		{
+			"**Go** and __Python__, *some* _Rust_ and `kubectl`",
+			[]Span{
+				{Text: "Go", Bold: true},
+				{Text: " and "},
+				{Text: "Python", Bold: true},
+				{Text: ", "},
+				{Text: "some", Italic: true},
+				{Text: " "},
+				{Text: "Rust", Italic: true},
+				{Text: " and "},
+				{Text: "kubectl", Code: true},
+			},
+		},
+		{
+			"See [my site](https://example.com/jane) for more",
+			[]Span{{Text: "See "}, {Text: "my site", URL: "https://example.com/jane"}, {Text: " for more"}},
+		},
+		// Underscores inside words are not emphasis
+		{"snake_case_name", []Span{{Text: "snake_case_name"}}},
+		{"an unclosed **bold", []Span{{Text: "an unclosed **bold"}}},
+	}
+	for _, tt := range tests {
+		if got := Spans(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Spans(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText("**Lead** on [the SOC](https://example.com) using `Splunk`")
+	if want := "Lead on the SOC using Splunk"; got != want {
+		t.Errorf("PlainText = %q, want %q", got, want)
+	}
+}
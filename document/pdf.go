@@ -0,0 +1,299 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// Page layout, in points (A4)
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 56.0
+	bulletIndent = 12.0
+	lineSpacing  = 1.35
+)
+
+// PDF fonts: the standard Helvetica faces every reader has
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	fontItalic  = "F3"
+)
+
+var fontNames = map[string]string{
+	fontRegular: "Helvetica",
+	fontBold:    "Helvetica-Bold",
+	fontItalic:  "Helvetica-Oblique",
+}
+
+// pdfStyle is how a kind of block is set
+type pdfStyle struct {
+	font   string
+	size   float64
+	before float64 // space above the block
+}
+
+var (
+	styleBody     = pdfStyle{fontRegular, 10.5, 4}
+	styleHeadings = map[int]pdfStyle{
+		1: {fontBold, 18, 0},
+		2: {fontBold, 12.5, 14},
+		3: {fontBold, 11, 8},
+	}
+	styleSmallHeading = pdfStyle{fontBold, 10.5, 6}
+)
+
+// PDF renders Markdown as an A4 PDF using the standard Helvetica fonts.
+// Characters outside Windows-1252 are replaced with "?". Inline bold and
+// italics are dropped, except that a paragraph wholly in bold or italics
+// is set in that face.
+func PDF(title, markdown string) []byte {
+	writer := &pdfWriter{y: pageHeight - pageMargin}
+	writer.newPage()
+
+	for i, block := range Parse(markdown) {
+		switch block.Kind {
+		case Heading:
+			style, ok := styleHeadings[block.Level]
+			if !ok {
+				style = styleSmallHeading
+			}
+			if i == 0 {
+				style.before = 0
+			}
+			writer.space(style.before)
+			writer.text(PlainText(block.Text), style, 0, "")
+			if block.Level == 2 {
+				writer.rule(2)
+			}
+		case Paragraph:
+			writer.space(styleBody.before)
+			style := styleBody
+			for _, line := range strings.Split(block.Text, "\n") {
+				spans := Spans(line)
+				if len(spans) == 1 && spans[0].Bold {
+					style.font = fontBold
+				} else if len(spans) == 1 && spans[0].Italic {
+					style.font = fontItalic
+				} else {
+					style.font = fontRegular
+				}
+				writer.text(PlainText(line), style, 0, "")
+			}
+		case List:
+			writer.space(styleBody.before)
+			for _, item := range block.Items {
+				indent := bulletIndent * float64(item.Depth)
+				writer.text(PlainText(item.Text), styleBody, indent, "•")
+			}
+		case Rule:
+			writer.space(6)
+			writer.rule(6)
+		}
+	}
+
+	return writer.bytes(title)
+}
+
+type pdfWriter struct {
+	pages   []*bytes.Buffer
+	content *bytes.Buffer
+	y       float64
+}
+
+func (w *pdfWriter) newPage() {
+	w.content = &bytes.Buffer{}
+	w.pages = append(w.pages, w.content)
+	w.y = pageHeight - pageMargin
+}
+
+func (w *pdfWriter) space(points float64) {
+	if w.y < pageHeight-pageMargin {
+		w.y -= points
+	}
+}
+
+// text wraps text to the page width and sets it line by line, starting a
+// new page when one is full. A bullet is hung in the indent.
+func (w *pdfWriter) text(text string, style pdfStyle, indent float64, bullet string) {
+	left := pageMargin + indent
+	if bullet != "" {
+		left += bulletIndent
+	}
+	lineHeight := style.size * lineSpacing
+
+	for i, line := range wrapText(text, style, pageWidth-pageMargin-left) {
+		if w.y-lineHeight < pageMargin {
+			w.newPage()
+		}
+		w.y -= lineHeight
+		if i == 0 && bullet != "" {
+			fmt.Fprintf(w.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+				style.font, style.size, left-bulletIndent, w.y, pdfString(bullet))
+		}
+		fmt.Fprintf(w.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+			style.font, style.size, left, w.y, pdfString(line))
+	}
+}
+
+// rule draws a thin line across the page below the last line
+func (w *pdfWriter) rule(gap float64) {
+	w.y -= gap
+	fmt.Fprintf(w.content, "0.8 G 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n",
+		pageMargin, w.y, pageWidth-pageMargin, w.y)
+	w.y -= gap
+}
+
+// bytes assembles the document: catalog, page tree, fonts, then each page
+// and its compressed content stream
+func (w *pdfWriter) bytes(title string) []byte {
+	var objects []string
+	add := func(object string) int {
+		objects = append(objects, object)
+		return len(objects)
+	}
+
+	catalog := add("") // filled in once the page tree's number is known
+	pagesID := add("")
+	info := add(fmt.Sprintf("<< /Title (%s) /Producer (JobHunter) >>", pdfString(title)))
+	fonts := map[string]int{}
+	for _, name := range []string{fontRegular, fontBold, fontItalic} {
+		fonts[name] = add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", fontNames[name]))
+	}
+	resources := fmt.Sprintf("<< /Font << /%s %d 0 R /%s %d 0 R /%s %d 0 R >> >>",
+		fontRegular, fonts[fontRegular], fontBold, fonts[fontBold], fontItalic, fonts[fontItalic])
+
+	var kids []string
+	for _, page := range w.pages {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(page.Bytes())
+		zw.Close()
+
+		contents := add(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+		pageID := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Contents %d 0 R /Resources %s >>",
+			pagesID, pageWidth, pageHeight, contents, resources))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID)
+	objects[pagesID-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, catalog, info, xref)
+	return out.Bytes()
+}
+
+// wrapText breaks text into lines no wider than width, splitting words that
+// are wider than a whole line
+func wrapText(text string, style pdfStyle, width float64) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if textWidth(candidate, style) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		for textWidth(word, style) > width {
+			cut := len([]rune(word))
+			for cut > 1 && textWidth(string([]rune(word)[:cut]), style) > width {
+				cut--
+			}
+			lines = append(lines, string([]rune(word)[:cut]))
+			word = string([]rune(word)[cut:])
+		}
+		line = word
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Glyph widths of Helvetica and Helvetica-Bold for ' ' through '~', in
+// thousandths of the font size, from the Adobe font metrics
+var (
+	helveticaWidths = []int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = []int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+func textWidth(text string, style pdfStyle) float64 {
+	widths := helveticaWidths
+	if style.font == fontBold {
+		widths = helveticaBoldWidths
+	}
+	total := 0
+	for _, r := range text {
+		if r >= ' ' && r <= '~' {
+			total += widths[r-' ']
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * style.size / 1000
+}
+
+// winAnsiCodes maps the characters Windows-1252 places in 0x80-0x9f
+var winAnsiCodes = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// pdfString encodes text as the body of a PDF literal string in
+// WinAnsiEncoding
+func pdfString(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		var b byte
+		switch code, ok := winAnsiCodes[r]; {
+		case ok:
+			b = code
+		case r >= ' ' && r <= '~', r >= 0xa0 && r <= 0xff:
+			b = byte(r)
+		case r == '\t':
+			b = ' '
+		default:
+			b = '?'
+		}
+		if b == '(' || b == ')' || b == '\\' {
+			out.WriteByte('\\')
+		}
+		out.WriteByte(b)
+	}
+	return out.String()
+}
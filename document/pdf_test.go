@@ -0,0 +1,70 @@
+package document_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/document"
+	"github.com/C9b3rD3vi1/jobhunter-tool/resume"
+)
+
+// TestPDFRoundTrip reads a generated PDF back with the resume importer, so
+// the writer and the reader are checked against each other
+func TestPDFRoundTrip(t *testing.T) {
+	markdown := "# Jane Doe\n" +
+		"Nairobi | jane@example.com\n" +
+		"\n" +
+		"## Experience\n" +
+		"**SOC Analyst**, Safaricom (2021 – Present)\n" +
+		"- Triaged alerts in *Splunk* & QRadar\n" +
+		"- Paid in KSh, not €\n" +
+		"\n" +
+		"---\n" +
+		"Unicode outside WinAnsi: 日本"
+
+	pdf := document.PDF("Jane (Doe) \\ Resume", markdown)
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF:\n%.200s", pdf)
+	}
+	if !bytes.Contains(pdf, []byte("/Title (Jane \\(Doe\\) \\\\ Resume)")) {
+		t.Errorf("title not escaped in the document info")
+	}
+
+	text, err := resume.ExtractText("resume.pdf", pdf)
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	want := []string{
+		"Jane Doe",
+		"Nairobi | jane@example.com",
+		"Experience",
+		"SOC Analyst, Safaricom (2021 – Present)",
+		"• Triaged alerts in Splunk & QRadar",
+		"• Paid in KSh, not €",
+		"Unicode outside WinAnsi: ??",
+	}
+	if got := strings.Split(text, "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("round trip =\n%s\nwant\n%s", text, strings.Join(want, "\n"))
+	}
+}
+
+func TestPDFPages(t *testing.T) {
+	var markdown strings.Builder
+	for i := 0; i < 120; i++ {
+		markdown.WriteString("- A highlight long enough to take a line of its own on the page\n")
+	}
+
+	pdf := document.PDF("Long", markdown.String())
+	if count := bytes.Count(pdf, []byte("/Type /Page ")); count < 2 {
+		t.Errorf("got %d pages, want the list to run onto a second page", count)
+	}
+
+	text, err := resume.ExtractText("resume.pdf", pdf)
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	if got := strings.Count(text, "A highlight long enough"); got != 120 {
+		t.Errorf("read back %d highlights, want 120", got)
+	}
+}
@@ -0,0 +1,109 @@
+package embeddings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxInputLength keeps inputs under the token limits of hosted models
+const maxInputLength = 8000
+
+// Provider turns texts into vectors. Vectors from the same provider and
+// model can be compared with Cosine.
+type Provider interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model identifies the provider and model, e.g. "openai/text-embedding-3-small",
+	// so vectors from different models are never compared
+	Model() string
+}
+
+// NewProviderFromEnv picks a provider from EMBEDDINGS_PROVIDER:
+//
+//	openai - OpenAI, using OPENAI_API_KEY and optionally EMBEDDINGS_MODEL
+//	local  - a local model server at EMBEDDINGS_URL (default http://localhost:11434)
+//	hash   - the offline hash embedder (the default)
+func NewProviderFromEnv() (Provider, error) {
+	model := os.Getenv("EMBEDDINGS_MODEL")
+
+	switch provider := strings.ToLower(os.Getenv("EMBEDDINGS_PROVIDER")); provider {
+	case "", "hash":
+		return NewHashProvider(DefaultHashDimensions), nil
+	case "openai":
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("EMBEDDINGS_PROVIDER is openai but OPENAI_API_KEY is not set")
+		}
+		return NewOpenAIProvider(apiKey, model), nil
+	case "local":
+		return NewLocalProvider(os.Getenv("EMBEDDINGS_URL"), model), nil
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDINGS_PROVIDER %q (want openai, local or hash)", provider)
+	}
+}
+
+// PrepareInput trims text to a length every provider accepts and flattens
+// newlines, which hosted models handle poorly. It cuts on a character
+// boundary, so multi-byte characters are never split.
+func PrepareInput(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) > maxInputLength {
+		cut := maxInputLength
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+	return text
+}
+
+// ContentHash identifies a text embedded by a model, so unchanged texts are
+// not embedded twice
+func ContentHash(model, text string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 if they differ
+// in length or either is all zeros
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// Encode packs a vector into bytes for storage
+func Encode(vector []float32) []byte {
+	data := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(value))
+	}
+	return data
+}
+
+// Decode unpacks a vector stored with Encode
+func Decode(data []byte) []float32 {
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector
+}
@@ -0,0 +1,112 @@
+package embeddings
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHashProviderDeterministic(t *testing.T) {
+	p := NewHashProvider(64)
+	if p.Model() != "hash/64" {
+		t.Errorf("Model = %q", p.Model())
+	}
+
+	texts := []string{"SOC analyst triaging Splunk alerts", "Frontend developer", ""}
+	first, err := p.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := NewHashProvider(64).Embed(context.Background(), texts)
+	if !reflect.DeepEqual(first, second) {
+		t.Error("the same texts embedded differently")
+	}
+
+	for i, vector := range first {
+		if len(vector) != 64 {
+			t.Errorf("vector %d has %d dimensions, want 64", i, len(vector))
+		}
+	}
+	if norm := Cosine(first[0], first[0]); math.Abs(norm-1) > 1e-6 {
+		t.Errorf("a vector's similarity to itself = %v, want 1", norm)
+	}
+	for _, value := range first[2] {
+		if value != 0 {
+			t.Fatalf("empty text embedded as %v, want all zeros", first[2])
+		}
+	}
+}
+
+func TestHashProviderSimilarity(t *testing.T) {
+	vectors, _ := NewHashProvider(DefaultHashDimensions).Embed(context.Background(), []string{
+		"SOC analyst triaging Splunk SIEM alerts",
+		"Security analyst monitoring SIEM alerts in Splunk",
+		"Pastry chef baking bread and cakes",
+	})
+	related, unrelated := Cosine(vectors[0], vectors[1]), Cosine(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Errorf("related texts %.2f, unrelated %.2f", related, unrelated)
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{"same direction", []float32{1, 2, 3}, []float32{2, 4, 6}, 1},
+		{"opposite", []float32{1, 0}, []float32{-1, 0}, -1},
+		{"orthogonal", []float32{1, 0}, []float32{0, 1}, 0},
+		{"zero vector", []float32{0, 0}, []float32{1, 1}, 0},
+		{"different lengths", []float32{1, 0}, []float32{1, 0, 0}, 0},
+		{"empty", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Cosine = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	vector := []float32{0, 1, -1, 0.125, 3.4e38, float32(math.Inf(-1))}
+	data := Encode(vector)
+	if len(data) != 4*len(vector) {
+		t.Errorf("encoded %d bytes, want %d", len(data), 4*len(vector))
+	}
+	if got := Decode(data); !reflect.DeepEqual(got, vector) {
+		t.Errorf("Decode(Encode(v)) = %v, want %v", got, vector)
+	}
+	if got := Decode(nil); len(got) != 0 {
+		t.Errorf("Decode(nil) = %v", got)
+	}
+}
+
+func TestPrepareInput(t *testing.T) {
+	if got := PrepareInput("  SOC\n\nanalyst\t role "); got != "SOC analyst role" {
+		t.Errorf("PrepareInput = %q", got)
+	}
+
+	// "é" is two bytes, so the limit falls in the middle of one
+	long := "a" + strings.Repeat("é", maxInputLength)
+	got := PrepareInput(long)
+	if !utf8.ValidString(got) {
+		t.Error("PrepareInput split a multi-byte character")
+	}
+	if len(got) != maxInputLength-1 {
+		t.Errorf("PrepareInput kept %d bytes, want %d", len(got), maxInputLength-1)
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	if ContentHash("hash/512", "text") == ContentHash("openai/text-embedding-3-small", "text") {
+		t.Error("the same text hashed the same under different models")
+	}
+	if ContentHash("hash/512", "text") != ContentHash("hash/512", "text") {
+		t.Error("ContentHash is not stable")
+	}
+}
@@ -0,0 +1,73 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/relevance"
+)
+
+// DefaultHashDimensions is the vector size used by the hash embedder
+const DefaultHashDimensions = 512
+
+// HashProvider is a deterministic, offline embedder. Each word and pair of
+// adjacent words is hashed to a dimension and sign, so texts sharing
+// vocabulary point the same way. It needs no network access, which makes it
+// the default and suitable for tests.
+type HashProvider struct {
+	dimensions int
+}
+
+func NewHashProvider(dimensions int) *HashProvider {
+	return &HashProvider{dimensions: dimensions}
+}
+
+func (p *HashProvider) Model() string {
+	return fmt.Sprintf("hash/%d", p.dimensions)
+}
+
+func (p *HashProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = p.embed(text)
+	}
+	return vectors, nil
+}
+
+func (p *HashProvider) embed(text string) []float32 {
+	vector := make([]float32, p.dimensions)
+	terms := relevance.Tokenize(text)
+	for i, term := range terms {
+		p.add(vector, term, 1)
+		if i > 0 {
+			p.add(vector, terms[i-1]+" "+term, 0.5)
+		}
+	}
+
+	// Unit length, so long descriptions don't dominate
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+	return vector
+}
+
+func (p *HashProvider) add(vector []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	index := int(sum % uint64(p.dimensions))
+	if sum&(1<<63) != 0 {
+		weight = -weight
+	}
+	vector[index] += weight
+}
@@ -0,0 +1,51 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OpenAIProvider embeds texts with the OpenAI embeddings API
+type OpenAIProvider struct {
+	client *openai.Client
+	model  openai.EmbeddingModel
+}
+
+// NewOpenAIProvider uses text-embedding-3-small unless another model is given
+func NewOpenAIProvider(apiKey, model string) *OpenAIProvider {
+	if model == "" {
+		model = string(openai.SmallEmbedding3)
+	}
+	return &OpenAIProvider{
+		client: openai.NewClient(apiKey),
+		model:  openai.EmbeddingModel(model),
+	}
+}
+
+func (p *OpenAIProvider) Model() string {
+	return "openai/" + string(p.model)
+}
+
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: texts,
+		Model: p.model,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openai embeddings: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("openai embeddings: got %d vectors for %d texts", len(resp.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range resp.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("openai embeddings: unexpected index %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
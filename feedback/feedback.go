@@ -0,0 +1,156 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/relevance"
+)
+
+// Signals a job can be given
+const (
+	Liked     = "liked"
+	Dismissed = "dismissed"
+)
+
+// MinExamples is how many rated jobs, with at least one of each kind, the
+// model needs before its predictions are used
+const MinExamples = 4
+
+// Training settings for the logistic regression
+const (
+	epochs       = 300
+	learningRate = 0.5
+	l2           = 0.01 // keeps weights small when there are few examples
+)
+
+// Features describes a job as a set of named binary features: title words,
+// listed skills and tech, company, work mode, employment type, source and
+// country
+func Features(job *models.Job) map[string]float64 {
+	features := map[string]float64{}
+	add := func(kind, value string) {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			features[kind+":"+value] = 1
+		}
+	}
+
+	for _, term := range relevance.Tokenize(job.Title) {
+		add("title", term)
+	}
+	for _, skill := range jsonList(job.Skills) {
+		add("skill", skill)
+	}
+	for _, tech := range jsonList(job.TechStack) {
+		add("tech", tech)
+	}
+	add("company", job.Company)
+	add("mode", job.WorkMode)
+	add("type", job.EmploymentType)
+	add("source", job.Source)
+	add("country", job.Country)
+	return features
+}
+
+func jsonList(data []byte) []string {
+	var list []string
+	json.Unmarshal(data, &list)
+	return list
+}
+
+// Example is a rated job: label 1 for liked or applied to, 0 for dismissed
+type Example struct {
+	Features map[string]float64
+	Label    float64
+}
+
+// Model is a logistic regression over job features
+type Model struct {
+	Weights   map[string]float64
+	Bias      float64
+	Positives int
+	Negatives int
+}
+
+// Train fits a model to the examples with batch gradient descent. It returns
+// nil until there are MinExamples examples including both labels.
+func Train(examples []Example) *Model {
+	model := &Model{Weights: map[string]float64{}}
+	for _, example := range examples {
+		if example.Label > 0.5 {
+			model.Positives++
+		} else {
+			model.Negatives++
+		}
+	}
+	if len(examples) < MinExamples || model.Positives == 0 || model.Negatives == 0 {
+		return nil
+	}
+
+	n := float64(len(examples))
+	for epoch := 0; epoch < epochs; epoch++ {
+		gradients := map[string]float64{}
+		biasGradient := 0.0
+		for _, example := range examples {
+			err := model.Predict(example.Features) - example.Label
+			for name, value := range example.Features {
+				gradients[name] += err * value
+			}
+			biasGradient += err
+		}
+
+		for name, gradient := range gradients {
+			model.Weights[name] -= learningRate * (gradient/n + l2*model.Weights[name])
+		}
+		model.Bias -= learningRate * biasGradient / n
+	}
+	return model
+}
+
+// Predict returns the probability that a job with these features is one
+// the user wants
+func (m *Model) Predict(features map[string]float64) float64 {
+	z := m.Bias
+	for name, value := range features {
+		z += m.Weights[name] * value
+	}
+	return 1 / (1 + math.Exp(-z))
+}
+
+// Explain lists the features that moved the prediction most, as "+title:soc"
+// for ones that raised it and "-mode:on-site" for ones that lowered it
+func (m *Model) Explain(features map[string]float64, limit int) []string {
+	type contribution struct {
+		name  string
+		value float64
+	}
+	var contributions []contribution
+	for name, value := range features {
+		if weight := m.Weights[name] * value; math.Abs(weight) >= 0.05 {
+			contributions = append(contributions, contribution{name, weight})
+		}
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		if a, b := math.Abs(contributions[i].value), math.Abs(contributions[j].value); a != b {
+			return a > b
+		}
+		return contributions[i].name < contributions[j].name
+	})
+
+	var explained []string
+	for i, c := range contributions {
+		if i == limit {
+			break
+		}
+		sign := "+"
+		if c.value < 0 {
+			sign = "-"
+		}
+		explained = append(explained, fmt.Sprintf("%s%s", sign, c.name))
+	}
+	return explained
+}
@@ -0,0 +1,112 @@
+package feedback
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+func TestFeatures(t *testing.T) {
+	job := &models.Job{
+		Title:     "Senior SOC Analyst",
+		Company:   " Acme Bank ",
+		Skills:    []byte(`["SIEM","Splunk"]`),
+		TechStack: []byte(`["AWS"]`),
+		WorkMode:  "Remote",
+		Source:    "BrighterMonday",
+	}
+	got := Features(job)
+
+	for _, name := range []string{"title:senior", "title:soc", "title:analyst", "skill:siem", "skill:splunk", "tech:aws", "company:acme bank", "mode:remote", "source:brightermonday"} {
+		if got[name] != 1 {
+			t.Errorf("missing feature %q in %v", name, got)
+		}
+	}
+	if _, ok := got["type:"]; ok {
+		t.Errorf("empty employment type became a feature: %v", got)
+	}
+}
+
+// ratedJob is an example with the given title words and work mode
+func ratedJob(title, mode string, label float64) Example {
+	return Example{Features: Features(&models.Job{Title: title, WorkMode: mode}), Label: label}
+}
+
+func TestTrainColdStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		examples []Example
+	}{
+		{"no ratings", nil},
+		{"too few", []Example{
+			ratedJob("SOC Analyst", "remote", 1),
+			ratedJob("Sales Executive", "on-site", 0),
+			ratedJob("Security Engineer", "remote", 1),
+		}},
+		{"only likes", []Example{
+			ratedJob("SOC Analyst", "remote", 1),
+			ratedJob("Security Engineer", "remote", 1),
+			ratedJob("Network Engineer", "hybrid", 1),
+			ratedJob("Cloud Engineer", "remote", 1),
+		}},
+		{"only dismissals", []Example{
+			ratedJob("Sales Executive", "on-site", 0),
+			ratedJob("Accountant", "on-site", 0),
+			ratedJob("Driver", "on-site", 0),
+			ratedJob("Cashier", "on-site", 0),
+		}},
+	}
+	for _, tt := range tests {
+		if model := Train(tt.examples); model != nil {
+			t.Errorf("%s: Train returned a model, want nil until %d mixed ratings", tt.name, MinExamples)
+		}
+	}
+}
+
+func TestTrainLearnsPreferences(t *testing.T) {
+	examples := []Example{
+		ratedJob("SOC Analyst", "remote", 1),
+		ratedJob("Security Analyst", "remote", 1),
+		ratedJob("SOC Engineer", "hybrid", 1),
+		ratedJob("Sales Executive", "on-site", 0),
+		ratedJob("Sales Manager", "on-site", 0),
+	}
+	model := Train(examples)
+	if model == nil {
+		t.Fatal("Train returned nil")
+	}
+	if model.Positives != 3 || model.Negatives != 2 {
+		t.Errorf("counted %d liked and %d dismissed, want 3 and 2", model.Positives, model.Negatives)
+	}
+
+	liked := model.Predict(Features(&models.Job{Title: "SOC Lead", WorkMode: "remote"}))
+	dismissed := model.Predict(Features(&models.Job{Title: "Sales Lead", WorkMode: "on-site"}))
+	if liked <= 0.5 || dismissed >= 0.5 {
+		t.Errorf("Predict = %.2f for a SOC job and %.2f for a sales job", liked, dismissed)
+	}
+
+	explained := model.Explain(Features(&models.Job{Title: "Sales Lead", WorkMode: "on-site"}), 2)
+	if len(explained) != 2 || !slices.Contains(explained, "-title:sale") {
+		t.Errorf("Explain = %v, want the two strongest features including -title:sale", explained)
+	}
+}
+
+func TestPredictStaysAProbability(t *testing.T) {
+	model := &Model{Weights: map[string]float64{"title:soc": 1e6, "title:sales": -1e6}}
+
+	tests := map[string]float64{
+		"title:soc":   1,
+		"title:sales": 0,
+	}
+	for feature, want := range tests {
+		got := model.Predict(map[string]float64{feature: 1})
+		if math.IsNaN(got) || got < 0 || got > 1 || math.Abs(got-want) > 1e-9 {
+			t.Errorf("Predict(%s) = %v, want %v", feature, got, want)
+		}
+	}
+	if got := model.Predict(nil); got != 0.5 {
+		t.Errorf("Predict with no known features = %v, want 0.5", got)
+	}
+}
@@ -0,0 +1,213 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Precision levels a location can be resolved to
+const (
+	PrecisionCity    = "city"
+	PrecisionRegion  = "region"
+	PrecisionCountry = "country"
+)
+
+// Place is a location resolved against the bundled gazetteer
+type Place struct {
+	City      string  `json:"city"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Precision string  `json:"precision"`
+}
+
+// HasCoordinates reports whether the place is specific enough for distance
+// filtering; country-level matches only carry the capital's coordinates.
+func (p Place) HasCoordinates() bool {
+	return p.Precision == PrecisionCity || p.Precision == PrecisionRegion
+}
+
+//go:embed gazetteer.csv
+var gazetteerCSV string
+
+type entry struct {
+	place    Place
+	priority int
+}
+
+var (
+	loadOnce sync.Once
+	// names holds full names and multi-letter aliases, matched anywhere in the text
+	names map[string]entry
+	// codes holds short abbreviations (KE, UK, NBO), matched only as a whole segment
+	codes map[string]entry
+	// countries maps lowercase country names to their canonical spelling
+	countries map[string]string
+
+	maxNameWords int
+
+	// stopWords are dropped before matching so "Nairobi County" resolves like "Nairobi"
+	stopWords = map[string]bool{"county": true, "city": true, "region": true, "province": true, "state": true}
+)
+
+func load() {
+	names = make(map[string]entry)
+	codes = make(map[string]entry)
+	countries = make(map[string]string)
+
+	reader := csv.NewReader(strings.NewReader(gazetteerCSV))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		log.Printf("❌ Error loading gazetteer: %v", err)
+		return
+	}
+
+	for i, record := range records {
+		if i == 0 || len(record) < 6 {
+			continue // header or malformed row
+		}
+
+		lat, latErr := strconv.ParseFloat(record[4], 64)
+		lon, lonErr := strconv.ParseFloat(record[5], 64)
+		if latErr != nil || lonErr != nil {
+			log.Printf("⚠️ Skipping gazetteer row %d: invalid coordinates", i+1)
+			continue
+		}
+
+		e := entry{place: Place{Region: record[2], Country: record[3], Latitude: lat, Longitude: lon}}
+		switch record[0] {
+		case "city":
+			e.place.City = record[1]
+			e.place.Precision = PrecisionCity
+			e.priority = 3
+		case "county":
+			e.place.Precision = PrecisionRegion
+			e.priority = 2
+		default:
+			e.place.Precision = PrecisionCountry
+			e.priority = 1
+			countries[strings.ToLower(record[3])] = record[3]
+		}
+
+		keys := []string{record[1]}
+		if len(record) > 6 && record[6] != "" {
+			keys = append(keys, strings.Split(record[6], "|")...)
+		}
+		for _, key := range keys {
+			key = normalizeText(key)
+			if key == "" {
+				continue
+			}
+			if len(key) <= 3 {
+				codes[key] = e
+				continue
+			}
+			names[key] = e
+			if words := len(strings.Fields(key)); words > maxNameWords {
+				maxNameWords = words
+			}
+		}
+	}
+}
+
+// Normalize resolves free-text location such as "Upper Hill, Nairobi" or
+// "Kampala (UG)" to a city, region and country. The most specific match
+// wins; between equally specific matches the later comma-separated segment
+// wins, since addresses run from street to country ("Mombasa Road, Nairobi").
+func Normalize(raw string) (Place, bool) {
+	loadOnce.Do(load)
+
+	var best entry
+	bestSegment := -1
+	consider := func(e entry, segment int) {
+		if bestSegment < 0 || e.priority > best.priority || (e.priority == best.priority && segment > bestSegment) {
+			best = e
+			bestSegment = segment
+		}
+	}
+
+	for i, segment := range strings.FieldsFunc(raw, isSegmentSeparator) {
+		segment = normalizeText(segment)
+		if segment == "" {
+			continue
+		}
+		if e, ok := codes[segment]; ok {
+			consider(e, i)
+			continue
+		}
+
+		words := strings.Fields(segment)
+		for size := min(maxNameWords, len(words)); size > 0; size-- {
+			for start := 0; start+size <= len(words); start++ {
+				if e, ok := names[strings.Join(words[start:start+size], " ")]; ok {
+					consider(e, i)
+				}
+			}
+		}
+	}
+
+	return best.place, bestSegment >= 0
+}
+
+// CountryName returns the canonical gazetteer spelling for a country name or
+// abbreviation ("ke", "kenya"), or an empty string if unknown.
+func CountryName(query string) string {
+	loadOnce.Do(load)
+
+	key := normalizeText(query)
+	if name, ok := countries[key]; ok {
+		return name
+	}
+	if e, ok := codes[key]; ok && e.place.Precision == PrecisionCountry {
+		return e.place.Country
+	}
+	if e, ok := names[key]; ok && e.place.Precision == PrecisionCountry {
+		return e.place.Country
+	}
+	return ""
+}
+
+// DistanceKm returns the great-circle distance between two coordinates
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+func isSegmentSeparator(r rune) bool {
+	return r == ',' || r == '/' || r == '|' || r == '(' || r == ')' || r == ';' || r == '•'
+}
+
+// normalizeText lowercases, drops punctuation and stop words, and collapses spaces
+func normalizeText(text string) string {
+	text = strings.ToLower(strings.ReplaceAll(text, "'", ""))
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, text)
+
+	words := strings.Fields(text)
+	kept := words[:0]
+	for _, word := range words {
+		if !stopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
@@ -0,0 +1,85 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw       string
+		city      string
+		region    string
+		country   string
+		precision string
+	}{
+		{"Upper Hill, Nairobi", "Nairobi", "Nairobi", "Kenya", PrecisionCity},
+		{"Mombasa Road, Nairobi", "Nairobi", "Nairobi", "Kenya", PrecisionCity},
+		{"Kampala (UG)", "Kampala", "Central Region", "Uganda", PrecisionCity},
+		{"NBO", "Nairobi", "Nairobi", "Kenya", PrecisionCity},
+		{"Uasin Gishu County", "", "Uasin Gishu", "Kenya", PrecisionRegion},
+		{"Elgeyo-Marakwet", "", "Elgeyo Marakwet", "Kenya", PrecisionRegion},
+		{"Remote (KE)", "", "", "Kenya", PrecisionCountry},
+		{"UK", "", "", "United Kingdom", PrecisionCountry},
+	}
+	for _, tt := range tests {
+		place, ok := Normalize(tt.raw)
+		if !ok {
+			t.Errorf("Normalize(%q) found nothing", tt.raw)
+			continue
+		}
+		if place.City != tt.city || place.Region != tt.region || place.Country != tt.country || place.Precision != tt.precision {
+			t.Errorf("Normalize(%q) = %+v, want %s/%s/%s (%s)", tt.raw, place, tt.city, tt.region, tt.country, tt.precision)
+		}
+	}
+}
+
+func TestNormalizeUnknown(t *testing.T) {
+	// Short codes only match a whole segment, so "ke" inside a word is ignored
+	for _, raw := range []string{"", "Atlantis", "Keyworth"} {
+		if place, ok := Normalize(raw); ok {
+			t.Errorf("Normalize(%q) = %+v, want no match", raw, place)
+		}
+	}
+}
+
+func TestCountryName(t *testing.T) {
+	tests := map[string]string{
+		"Kenya":           "Kenya",
+		"ke":              "Kenya",
+		"USA":             "United States",
+		"the netherlands": "Netherlands",
+		"Nairobi":         "",
+		"Narnia":          "",
+	}
+	for query, want := range tests {
+		if got := CountryName(query); got != want {
+			t.Errorf("CountryName(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	nairobi, _ := Normalize("Nairobi")
+	mombasa, _ := Normalize("Mombasa")
+
+	// Nairobi to Mombasa is about 440 km as the crow flies
+	got := DistanceKm(nairobi.Latitude, nairobi.Longitude, mombasa.Latitude, mombasa.Longitude)
+	if math.Abs(got-440) > 10 {
+		t.Errorf("Nairobi to Mombasa = %.0f km, want about 440", got)
+	}
+	if back := DistanceKm(mombasa.Latitude, mombasa.Longitude, nairobi.Latitude, nairobi.Longitude); math.Abs(back-got) > 1e-9 {
+		t.Errorf("distance is not symmetric: %v and %v", got, back)
+	}
+	if same := DistanceKm(nairobi.Latitude, nairobi.Longitude, nairobi.Latitude, nairobi.Longitude); same != 0 {
+		t.Errorf("distance to itself = %v", same)
+	}
+}
+
+func TestHasCoordinates(t *testing.T) {
+	county, _ := Normalize("Laikipia")
+	country, _ := Normalize("Kenya")
+	if !county.HasCoordinates() || country.HasCoordinates() {
+		t.Errorf("HasCoordinates: county %v, country %v", county.HasCoordinates(), country.HasCoordinates())
+	}
+}
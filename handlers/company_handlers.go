@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/ai"
+	"github.com/C9b3rD3vi1/jobhunter-tool/briefing"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CompanyNotesRequest struct {
+	Notes string `json:"notes"`
+}
+
+// APICompanyBriefingHandler returns a company's briefing with the user's
+// notes and the last summary
+func APICompanyBriefingHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	name := companyParam(c)
+	if name == "" {
+		return c.Status(400).JSON(errorResponse("Company name is required"))
+	}
+
+	report, note, _ := companyBriefing(ctx, name)
+	return c.JSON(success("", fiber.Map{
+		"briefing":      report,
+		"salary_range":  report.Salary.Range(),
+		"summary":       note.Summary,
+		"summary_model": note.SummaryModel,
+		"summarized_at": note.SummarizedAt,
+	}))
+}
+
+// UpdateCompanyNotesHandler saves the user's notes on a company
+func UpdateCompanyNotesHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	name := companyParam(c)
+	if name == "" {
+		return c.Status(400).JSON(errorResponse("Company name is required"))
+	}
+
+	var req CompanyNotesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+
+	if err := ctx.DB.SaveCompanyNotes(name, strings.TrimSpace(req.Notes)); err != nil {
+		log.Printf("Error saving company notes: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save notes"))
+	}
+
+	return c.JSON(success("Notes saved"))
+}
+
+// SummarizeCompanyHandler has the AI provider summarize a company's
+// briefing and keeps the summary for the company page
+func SummarizeCompanyHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	name := companyParam(c)
+	if name == "" {
+		return c.Status(400).JSON(errorResponse("Company name is required"))
+	}
+
+	report, _, _ := companyBriefing(ctx, name)
+	if len(report.Postings) == 0 && len(report.Applications) == 0 && report.Notes == "" {
+		return c.Status(400).JSON(errorResponse("Nothing is known about this company yet"))
+	}
+
+	summary, err := ctx.AI.GenerateCompanySummary(c.UserContext(), report)
+	if errors.Is(err, ai.ErrNoProvider) {
+		return c.Status(400).JSON(errorResponse("Summaries need an AI provider; set LLM_PROVIDER to enable them"))
+	}
+	if err != nil {
+		log.Printf("Error summarizing company: %v", err)
+		return aiErrorResponse(c, err, "Failed to summarize company")
+	}
+
+	if err := ctx.DB.SaveCompanySummary(name, summary, ctx.AI.ModelName()); err != nil {
+		log.Printf("Error saving company summary: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save summary"))
+	}
+
+	return c.JSON(success("Briefing summarized", fiber.Map{"summary": summary, "model": ctx.AI.ModelName()}))
+}
+
+// companyBriefing compiles the briefing for a company along with the
+// user's notes and summary, and the company's postings for listing
+func companyBriefing(ctx *HandlerContext, name string) (briefing.Briefing, *models.CompanyNote, []models.Job) {
+	jobs, err := ctx.DB.GetJobsByCompany(name)
+	if err != nil {
+		log.Printf("Error fetching company jobs: %v", err)
+		// Fallback to client-side filtering
+		if allJobs, err := ctx.DB.GetJobs(100, 0); err == nil {
+			jobs = filterJobsByCompany(allJobs, name)
+		}
+	}
+
+	applications, err := ctx.DB.GetApplicationsByCompany(name)
+	if err != nil {
+		log.Printf("Error fetching company applications: %v", err)
+	}
+
+	note, err := ctx.DB.GetCompanyNote(name)
+	if err != nil {
+		log.Printf("Error fetching company notes: %v", err)
+		note = &models.CompanyNote{Company: name}
+	}
+
+	return briefing.Build(name, jobs, applications, note.Notes, time.Now()), note, jobs
+}
+
+// companyParam is the company name from the URL, which may be escaped
+func companyParam(c *fiber.Ctx) string {
+	name := c.Params("name")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return strings.TrimSpace(name)
+}
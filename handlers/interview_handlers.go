@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/ai"
+	"github.com/C9b3rD3vi1/jobhunter-tool/document"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/profile"
+	"github.com/C9b3rD3vi1/jobhunter-tool/sections"
+	"github.com/gofiber/fiber/v2"
+)
+
+// applicationStatuses are the stages an application moves through
+var applicationStatuses = []string{"Applied", "Interviewing", "Offer", "Rejected"}
+
+// interviewPrepTimeout bounds a prep pack written in the background
+const interviewPrepTimeout = 2 * time.Minute
+
+// Limits on what goes into the company notes
+const (
+	maxNotePostings     = 5
+	maxAboutCompanyText = 400
+)
+
+// preparing holds the applications whose prep pack is being written in the
+// background, so moving one to Interviewing twice doesn't start two
+var (
+	preparingMu sync.Mutex
+	preparing   = map[string]bool{}
+)
+
+type UpdateStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// UpdateApplicationStatusHandler moves an application to another stage.
+// Moving to Interviewing starts writing a prep pack if there isn't one.
+func UpdateApplicationStatusHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	var req UpdateStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+	status := ""
+	for _, known := range applicationStatuses {
+		if strings.EqualFold(known, strings.TrimSpace(req.Status)) {
+			status = known
+		}
+	}
+	if status == "" {
+		return c.Status(400).JSON(errorResponse("Status must be one of " + strings.Join(applicationStatuses, ", ")))
+	}
+
+	application, err := ctx.DB.GetApplication(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Application not found"))
+	}
+
+	if err := ctx.DB.UpdateApplicationStatus(application.ID, status); err != nil {
+		log.Printf("Error updating application status: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to update status"))
+	}
+
+	message := "Status updated"
+	data := fiber.Map{"status": status, "preparing": false}
+	if status == "Interviewing" && application.PrepPack == "" {
+		startInterviewPrep(ctx, application)
+		message = "Status updated. Your interview prep pack is being written."
+		data["preparing"] = true
+	}
+
+	return c.JSON(success(message, data))
+}
+
+// InterviewPrepHandler shows an application's interview prep pack
+func InterviewPrepHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	application, err := ctx.DB.GetApplication(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Application not found"))
+	}
+
+	return c.Render("interview-prep", fiber.Map{
+		"Page":        "tracker",
+		"Title":       fmt.Sprintf("Interview Prep - %s, %s", application.Role, application.Company),
+		"Application": application,
+		// Escaped by the Markdown renderer
+		"PrepHTML": template.HTML(document.HTMLFragment(application.PrepPack)),
+	})
+}
+
+// GenerateInterviewPrepHandler writes an application's prep pack now,
+// replacing any earlier one
+func GenerateInterviewPrepHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	application, err := ctx.DB.GetApplication(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Application not found"))
+	}
+
+	pack, err := ctx.AI.GenerateInterviewPrep(c.UserContext(), interviewPrepInput(ctx, application))
+	if err != nil {
+		log.Printf("Error generating interview prep: %v", err)
+		return aiErrorResponse(c, err, "Failed to generate prep pack")
+	}
+	if err := ctx.DB.SaveInterviewPrep(application.ID, pack, ctx.AI.ModelName()); err != nil {
+		log.Printf("Error saving interview prep: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save prep pack"))
+	}
+
+	return c.JSON(success("Prep pack generated", fiber.Map{"prep_pack": pack}))
+}
+
+// startInterviewPrep writes a prep pack in the background, so changing the
+// status doesn't wait on the provider. If the provider fails the template
+// pack is kept, and the user can regenerate from the prep page. Nothing is
+// started if a pack is already being written for the application.
+func startInterviewPrep(ctx *HandlerContext, application *models.Application) {
+	preparingMu.Lock()
+	if preparing[application.ID] {
+		preparingMu.Unlock()
+		return
+	}
+	preparing[application.ID] = true
+	preparingMu.Unlock()
+
+	input := interviewPrepInput(ctx, application)
+	go func() {
+		defer func() {
+			preparingMu.Lock()
+			delete(preparing, application.ID)
+			preparingMu.Unlock()
+		}()
+
+		background, cancel := context.WithTimeout(context.Background(), interviewPrepTimeout)
+		defer cancel()
+
+		model := ctx.AI.ModelName()
+		pack, err := ctx.AI.GenerateInterviewPrep(background, input)
+		if err != nil {
+			log.Printf("Error generating interview prep for %s: %v", application.ID, err)
+			if pack == "" {
+				return
+			}
+			model = "template"
+		}
+		if err := ctx.DB.SaveInterviewPrep(application.ID, pack, model); err != nil {
+			log.Printf("Error saving interview prep for %s: %v", application.ID, err)
+		}
+	}()
+}
+
+// interviewPrepInput gathers the job, the user's experience and what we
+// know about the company. Applications added by hand may have no job, in
+// which case the pack is built from the role and company alone.
+func interviewPrepInput(ctx *HandlerContext, application *models.Application) ai.InterviewPrepInput {
+	input := ai.InterviewPrepInput{
+		Job:           application.Role,
+		Company:       application.Company,
+		HiringManager: application.HiringManager,
+	}
+
+	user, err := ctx.DB.GetUserProfile()
+	if err != nil {
+		log.Printf("Error getting user profile: %v", err)
+	} else {
+		input.Name = user.Name
+		input.WorkHistory = profile.WorkHistory(user)
+	}
+
+	var job *models.Job
+	if application.JobID != "" {
+		if job, err = ctx.DB.GetJobByID(application.JobID); err != nil {
+			log.Printf("Error getting job %s: %v", application.JobID, err)
+			job = nil
+		}
+	}
+	if job != nil {
+		skills, err := ctx.DB.GetUserSkills()
+		if err != nil {
+			log.Printf("Error getting user skills: %v", err)
+		}
+		userCerts, err := ctx.DB.GetUserCertifications()
+		if err != nil {
+			log.Printf("Error getting user certifications: %v", err)
+		}
+		analysis := ctx.AI.GenerateSkillsAnalysis(job.Description, skills, userCerts, user)
+
+		input.Description = job.Description
+		input.Skills = ParseSkillsFromJSON(job.Skills)
+		if len(input.Skills) == 0 {
+			input.Skills = append(append([]string{}, analysis.MatchingSkills...), analysis.MissingSkills...)
+		}
+		input.MatchingSkills = analysis.MatchingSkills
+		input.MissingSkills = analysis.MissingSkills
+		input.Requirements = requirementLines(ParseSectionsFromJSON(job.Sections, job.Description))
+	}
+
+	input.CompanyNotes = companyNotes(ctx, application, job)
+	return input
+}
+
+// requirementLines splits the requirements section into one line per
+// requirement, without bullets
+func requirementLines(parsed []sections.Section) []string {
+	var lines []string
+	for _, line := range strings.Split(sections.Text(parsed, sections.Requirements), "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•·–"))
+		if len(line) >= 10 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// companyNotes lists what we know about the company and the role: the
+// posting's details, its about-us text, other postings and applications,
+// and the user's notes on the application and the company
+func companyNotes(ctx *HandlerContext, application *models.Application, job *models.Job) []string {
+	var notes []string
+
+	if job != nil {
+		var details []string
+		for _, detail := range []string{job.EmploymentType, job.WorkMode, job.Location} {
+			if detail = strings.TrimSpace(detail); detail != "" {
+				details = append(details, detail)
+			}
+		}
+		if len(details) > 0 {
+			notes = append(notes, "The role: "+strings.Join(details, ", "))
+		}
+		if job.SalaryRange != "" {
+			notes = append(notes, "Advertised salary: "+job.SalaryRange)
+		}
+		if about := strings.Join(strings.Fields(sections.Text(ParseSectionsFromJSON(job.Sections, job.Description), sections.AboutCompany)), " "); about != "" {
+			if runes := []rune(about); len(runes) > maxAboutCompanyText {
+				about = strings.TrimSpace(string(runes[:maxAboutCompanyText])) + "…"
+			}
+			notes = append(notes, "From the posting: "+about)
+		}
+	}
+
+	postings, err := ctx.DB.GetJobsByCompany(application.Company)
+	if err != nil {
+		log.Printf("Error getting company jobs: %v", err)
+	}
+	var titles []string
+	for _, posting := range postings {
+		if posting.ID != application.JobID && !containsFold(titles, posting.Title) {
+			titles = append(titles, posting.Title)
+		}
+	}
+	if len(titles) > 0 {
+		more := ""
+		if len(titles) > maxNotePostings {
+			more = fmt.Sprintf(" and %d more", len(titles)-maxNotePostings)
+			titles = titles[:maxNotePostings]
+		}
+		notes = append(notes, fmt.Sprintf("Also hiring for: %s%s", strings.Join(titles, ", "), more))
+	}
+
+	applications, err := ctx.DB.GetApplicationsByCompany(application.Company)
+	if err != nil {
+		log.Printf("Error getting company applications: %v", err)
+	}
+	for _, other := range applications {
+		if other.ID != application.ID {
+			notes = append(notes, fmt.Sprintf("You applied for %s on %s (%s)", other.Role, other.AppliedDate, other.Status))
+		}
+	}
+
+	if notesText := strings.TrimSpace(application.Notes); notesText != "" {
+		notes = append(notes, "Your notes: "+notesText)
+	}
+	if note, err := ctx.DB.GetCompanyNote(application.Company); err != nil {
+		log.Printf("Error getting company notes: %v", err)
+	} else if note.Notes != "" {
+		notes = append(notes, "Your notes on "+application.Company+": "+note.Notes)
+	}
+	return notes
+}
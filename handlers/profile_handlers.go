@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/database"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/profile"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/datatypes"
+)
+
+type UserProfileRequest struct {
+	Name            string                  `json:"name"`
+	Headline        string                  `json:"headline"`
+	Summary         string                  `json:"summary"`
+	YearsExperience int                     `json:"years_experience"`
+	WorkHistory     []models.WorkExperience `json:"work_history"`
+	Education       []models.Education      `json:"education"`
+	Email           string                  `json:"email"`
+	Phone           string                  `json:"phone"`
+	Location        string                  `json:"location"`
+	LinkedIn        string                  `json:"linkedin"`
+	Website         string                  `json:"website"`
+	TargetRoles     []string                `json:"target_roles"`
+	TargetLocations []string                `json:"target_locations"`
+	SalaryMin       int                     `json:"salary_min"`
+	SalaryMax       int                     `json:"salary_max"`
+}
+
+// ProfileHandler displays the user profile page
+func ProfileHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	user, err := ctx.DB.GetUserProfile()
+	if err != nil {
+		log.Printf("Error getting user profile: %v", err)
+		user = database.DefaultUserProfile()
+	}
+
+	latest, err := ctx.DB.GetLatestResume()
+	if err != nil {
+		log.Printf("Error getting resume: %v", err)
+	}
+	// A resume waiting for review shows what it would add
+	var proposal *models.ResumeProposal
+	if latest != nil && latest.ReviewedAt == nil && len(latest.Proposal) > 0 {
+		proposal = &models.ResumeProposal{}
+		json.Unmarshal(latest.Proposal, proposal)
+	}
+
+	return c.Render("profile", fiber.Map{
+		"Page":            "profile",
+		"Title":           "Your Profile",
+		"Profile":         user,
+		"WorkHistory":     profile.WorkHistory(user),
+		"Education":       profile.Education(user),
+		"TargetRoles":     strings.Join(profile.TargetRoles(user), ", "),
+		"TargetLocations": strings.Join(profile.TargetLocations(user), ", "),
+		"Resume":          latest,
+		"ResumeProposal":  proposal,
+	})
+}
+
+// UpdateProfileHandler saves the user profile and re-scores jobs against it
+func UpdateProfileHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	var req UserProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+
+	if req.YearsExperience < 0 || req.YearsExperience > 60 {
+		return c.Status(400).JSON(errorResponse("Years of experience must be between 0 and 60"))
+	}
+	if req.SalaryMin < 0 || req.SalaryMax < 0 {
+		return c.Status(400).JSON(errorResponse("Salary cannot be negative"))
+	}
+	if req.SalaryMax > 0 && req.SalaryMax < req.SalaryMin {
+		return c.Status(400).JSON(errorResponse("Maximum salary cannot be below the minimum"))
+	}
+
+	// Drop the blank rows the form adds
+	history := []models.WorkExperience{}
+	for _, job := range req.WorkHistory {
+		job.Title, job.Company = strings.TrimSpace(job.Title), strings.TrimSpace(job.Company)
+		if job.Title == "" && job.Company == "" {
+			continue
+		}
+		var highlights []string
+		for _, highlight := range job.Highlights {
+			if highlight = strings.TrimSpace(highlight); highlight != "" {
+				highlights = append(highlights, highlight)
+			}
+		}
+		job.Highlights = highlights
+		history = append(history, job)
+	}
+	education := []models.Education{}
+	for _, entry := range req.Education {
+		if strings.TrimSpace(entry.Qualification) == "" && strings.TrimSpace(entry.Institution) == "" {
+			continue
+		}
+		education = append(education, entry)
+	}
+
+	historyJSON, _ := json.Marshal(history)
+	educationJSON, _ := json.Marshal(education)
+
+	user := models.UserProfile{
+		Name:            strings.TrimSpace(req.Name),
+		Headline:        strings.TrimSpace(req.Headline),
+		Summary:         strings.TrimSpace(req.Summary),
+		YearsExperience: req.YearsExperience,
+		WorkHistory:     datatypes.JSON(historyJSON),
+		Education:       datatypes.JSON(educationJSON),
+		Email:           strings.TrimSpace(req.Email),
+		Phone:           strings.TrimSpace(req.Phone),
+		Location:        strings.TrimSpace(req.Location),
+		LinkedIn:        strings.TrimSpace(req.LinkedIn),
+		Website:         strings.TrimSpace(req.Website),
+		TargetRoles:     stringListJSON(req.TargetRoles),
+		TargetLocations: stringListJSON(req.TargetLocations),
+		SalaryMin:       req.SalaryMin,
+		SalaryMax:       req.SalaryMax,
+	}
+
+	if err := ctx.DB.SaveUserProfile(&user); err != nil {
+		log.Printf("Error saving user profile: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save profile"))
+	}
+
+	ctx.Scraper.StartRescore("profile changed")
+
+	return c.JSON(success("Profile saved. Jobs are being re-scored in the background.", user))
+}
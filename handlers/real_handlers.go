@@ -1,17 +1,26 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"log"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/C9b3rD3vi1/jobhunter-tool/ai"
+	"github.com/C9b3rD3vi1/jobhunter-tool/certifications"
 	"github.com/C9b3rD3vi1/jobhunter-tool/database"
+	"github.com/C9b3rD3vi1/jobhunter-tool/document"
+	"github.com/C9b3rD3vi1/jobhunter-tool/feedback"
+	"github.com/C9b3rD3vi1/jobhunter-tool/geo"
 	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/profile"
 	"github.com/C9b3rD3vi1/jobhunter-tool/scraper"
+	"github.com/C9b3rD3vi1/jobhunter-tool/sections"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -30,16 +39,56 @@ type AnalyzeSkillsRequest struct {
 }
 
 type CoverLetterRequest struct {
+	JobID          string `json:"job_id"` // optional; fills in the fields below from a saved job
 	JobTitle       string `json:"job_title" validate:"required"`
 	Company        string `json:"company" validate:"required"`
 	JobDescription string `json:"job_description" validate:"required"`
-	UserProfile    string `json:"user_profile"`
+	UserProfile    string `json:"user_profile"` // defaults to a description built from the profile page
+	UserName       string `json:"user_name"`    // defaults to the profile's name
+	Tone           string `json:"tone"`         // e.g. professional, warm, concise
+	Length         int    `json:"length"`       // in words
+}
+
+// input converts the request to the cover letter template variables
+func (req CoverLetterRequest) input() ai.CoverLetterInput {
+	return ai.CoverLetterInput{
+		Name:        req.UserName,
+		Job:         req.JobTitle,
+		Company:     req.Company,
+		Description: req.JobDescription,
+		Profile:     req.UserProfile,
+		Tone:        req.Tone,
+		Length:      req.Length,
+	}
+}
+
+type CoverLetterEditRequest struct {
+	Content string `json:"content"`
+}
+
+type ApplyRequest struct {
+	AppliedDate   string `json:"applied_date" form:"applied_date"`
+	Notes         string `json:"notes" form:"notes"`
+	CoverLetterID uint   `json:"cover_letter_id" form:"cover_letter_id"` // defaults to the job's latest letter
+	// defaults to the job's latest tailored resume
+	TailoredResumeID uint `json:"tailored_resume_id" form:"tailored_resume_id"`
+}
+
+type JobFeedbackRequest struct {
+	Signal string `json:"signal"` // liked, dismissed, or empty to clear
 }
 
 type AddSkillRequest struct {
 	Skill string `json:"skill" validate:"required"`
 }
 
+type AddCertificationRequest struct {
+	Name         string `json:"name" validate:"required"`
+	CredentialID string `json:"credential_id"`
+	IssuedDate   string `json:"issued_date"`
+	ExpiryDate   string `json:"expiry_date"`
+}
+
 type AddApplicationRequest struct {
 	JobID         string `json:"job_id"`
 	Company       string `json:"company" validate:"required"`
@@ -70,6 +119,30 @@ func errorResponse(message string) Response {
 	}
 }
 
+// aiErrorResponse maps a failed model call to a status code and message
+func aiErrorResponse(c *fiber.Ctx, err error, message string) error {
+	status, message := aiErrorStatus(err, message)
+	return c.Status(status).JSON(errorResponse(message))
+}
+
+func aiErrorStatus(err error, message string) (int, string) {
+	switch ai.KindOf(err) {
+	case ai.ErrRateLimited:
+		return 429, message + ": the AI provider is rate limiting requests, try again shortly"
+	case ai.ErrTimeout:
+		return 504, message + ": the AI provider took too long to respond"
+	case ai.ErrCanceled:
+		// A streaming client went away; nobody will read this
+		return 499, message + ": request canceled"
+	case ai.ErrUnavailable, ai.ErrEmptyResponse, ai.ErrBadRequest:
+		return 502, message + ": the AI provider returned an error"
+	case ai.ErrUnauthorized:
+		return 502, message + ": the AI provider rejected the configured API key"
+	default:
+		return 500, message
+	}
+}
+
 // HandlerContext provides common dependencies for handlers
 type HandlerContext struct {
 	DB      *database.DB
@@ -77,13 +150,22 @@ type HandlerContext struct {
 	AI      *ai.AIGenerator
 }
 
+// requestTimeout bounds everything done for one request, including every
+// retry of an AI call
+const requestTimeout = 90 * time.Second
+
+// RequestContext gives each request a context with an overall deadline.
+// Handlers pass c.UserContext() to AI calls so retries cannot outlast it.
+func RequestContext(c *fiber.Ctx) error {
+	requestCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	c.SetUserContext(requestCtx)
+	return c.Next()
+}
+
 // getHandlerContext extracts common dependencies from Fiber context
 func getHandlerContext(c *fiber.Ctx) *HandlerContext {
-	return &HandlerContext{
-		DB:      c.Locals("db").(*database.DB),
-		Scraper: c.Locals("scraper").(*scraper.RealScraper),
-		AI:      c.Locals("ai").(*ai.AIGenerator),
-	}
+	return c.Locals("deps").(*HandlerContext)
 }
 
 // IndexHandler displays the dashboard
@@ -121,25 +203,85 @@ func JobsHandler(c *fiber.Ctx) error {
 	limit := 20
 	offset := (page - 1) * limit
 
-	jobs, err := ctx.DB.GetJobs(limit, offset)
+	sortOrder := c.Query("sort", "")
+	filters, err := parseJobFilters(c)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse(err.Error()))
+	}
+
+	jobs, total, err := getSortedJobs(ctx.DB, sortOrder, filters, limit, offset)
 	if err != nil {
 		log.Printf("Error fetching jobs: %v", err)
 		return c.Status(500).JSON(errorResponse("Failed to fetch jobs"))
 	}
 
-	filters := parseJobFilters(c)
-	filteredJobs := applyJobFilters(jobs, filters)
-
 	return c.Render("jobs", fiber.Map{
-		"Page":         "jobs",
-		"Title":        "Job Board",
-		"Jobs":         filteredJobs,
-		"CurrentPage":  page,
-		"HasNext":      len(jobs) == limit,
-		"ScoreFilter":  filters.Score,
-		"SkillFilter":  filters.Skill,
-		"CompanyFilter": filters.Company,
-		"LocationFilter": filters.Location,
+		"Page":                 "jobs",
+		"Title":                "Job Board",
+		"Jobs":                 jobs,
+		"CurrentPage":          page,
+		"HasNext":              offset+len(jobs) < total,
+		"ScoreFilter":          filters.Score,
+		"SkillFilter":          filters.Skill,
+		"CompanyFilter":        filters.Company,
+		"LocationFilter":       filters.Location,
+		"EmploymentTypeFilter": filters.EmploymentType,
+		"WorkModeFilter":       filters.WorkMode,
+		"DeadlineFilter":       filters.Deadline,
+		"CountryFilter":        filters.Country,
+		"NearFilter":           filters.Near,
+		"RadiusFilter":         filters.Radius,
+		"SortOrder":            sortOrder,
+	})
+}
+
+// getSortedJobs returns a page of the jobs matching the filters, by score or
+// with sort "fresh" by score decayed by age and boosted by an approaching
+// deadline, along with how many jobs match in total
+func getSortedJobs(db *database.DB, sortOrder string, filters JobFilters, limit, offset int) ([]models.Job, int, error) {
+	// Filters and freshness are applied in Go, so they run over every job
+	// before the page is taken
+	jobs, err := db.GetJobs(-1, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if sortOrder == "fresh" {
+		scraper.SortByFreshness(jobs, time.Now())
+	}
+	jobs = applyJobFilters(jobs, filters)
+
+	total := len(jobs)
+	if offset < 0 || offset >= total || limit < 1 {
+		return []models.Job{}, total, nil
+	}
+	return jobs[offset:min(offset+limit, total)], total, nil
+}
+
+// FilteredJob is a job dropped or demoted by exclusion rules, with the reasons
+type FilteredJob struct {
+	models.Job
+	Reasons []string
+}
+
+// FilteredJobsHandler shows the jobs exclusion rules dropped or demoted and why
+func FilteredJobsHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	jobs, err := ctx.DB.GetFilteredJobs()
+	if err != nil {
+		log.Printf("Error fetching filtered jobs: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to fetch filtered jobs"))
+	}
+
+	filtered := make([]FilteredJob, 0, len(jobs))
+	for _, job := range jobs {
+		filtered = append(filtered, FilteredJob{Job: job, Reasons: ParseSkillsFromJSON(job.FilterReasons)})
+	}
+
+	return c.Render("filtered", fiber.Map{
+		"Page":  "jobs",
+		"Title": "Filtered Jobs",
+		"Jobs":  filtered,
 	})
 }
 
@@ -151,7 +293,17 @@ func APIJobsHandler(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
 	offset := (page - 1) * limit
 
-	jobs, err := ctx.DB.GetJobs(limit, offset)
+	sortOrder := c.Query("sort", "")
+	if sortOrder != "" && sortOrder != "score" && sortOrder != "fresh" {
+		return c.Status(400).JSON(errorResponse("Sort must be score or fresh"))
+	}
+
+	filters, err := parseJobFilters(c)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse(err.Error()))
+	}
+
+	jobs, total, err := getSortedJobs(ctx.DB, sortOrder, filters, limit, offset)
 	if err != nil {
 		log.Printf("Error fetching jobs for API: %v", err)
 		return c.Status(500).JSON(errorResponse("Failed to fetch jobs"))
@@ -162,7 +314,7 @@ func APIJobsHandler(c *fiber.Ctx) error {
 		"pagination": fiber.Map{
 			"page":  page,
 			"limit": limit,
-			"total": len(jobs),
+			"total": total,
 		},
 	}))
 }
@@ -180,6 +332,45 @@ func APIStatsHandler(c *fiber.Ctx) error {
 	return c.JSON(success("Statistics retrieved successfully", stats))
 }
 
+// APIJobScoreHandler returns the score breakdown for a job as JSON
+func APIJobScoreHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	job, err := ctx.DB.GetJobByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Job not found"))
+	}
+
+	return c.JSON(success("Score breakdown retrieved successfully", jobScoreBreakdown(c, job)))
+}
+
+// similarJobsLimit is how many similar jobs the detail page shows
+const similarJobsLimit = 5
+
+// APIJobSimilarHandler returns the jobs most similar to a job. The number
+// returned can be set with ?limit= (default 5, at most 20).
+func APIJobSimilarHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	job, err := ctx.DB.GetJobByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Job not found"))
+	}
+
+	limit := c.QueryInt("limit", similarJobsLimit)
+	if limit < 1 || limit > 20 {
+		return c.Status(400).JSON(errorResponse("Limit must be between 1 and 20"))
+	}
+
+	similar, err := ctx.Scraper.SimilarJobs(c.UserContext(), job, limit)
+	if err != nil {
+		log.Printf("Error finding similar jobs: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to find similar jobs"))
+	}
+
+	return c.JSON(success("Similar jobs retrieved successfully", similar))
+}
+
 // JobDetailHandler displays details for a specific job
 func JobDetailHandler(c *fiber.Ctx) error {
 	ctx := getHandlerContext(c)
@@ -194,13 +385,89 @@ func JobDetailHandler(c *fiber.Ctx) error {
 		return c.Status(404).JSON(errorResponse("Job not found"))
 	}
 
+	signal, err := ctx.DB.GetJobFeedback(job.ID)
+	if err != nil {
+		log.Printf("Error getting job feedback: %v", err)
+	}
+
+	similar, err := ctx.Scraper.SimilarJobs(c.UserContext(), job, similarJobsLimit)
+	if err != nil {
+		log.Printf("Error finding similar jobs: %v", err)
+	}
+
+	var coverLetter *models.CoverLetter
+	if latest, err := ctx.DB.GetLatestCoverLetter(job.ID); err != nil {
+		log.Printf("Error getting cover letter: %v", err)
+	} else if latest != nil {
+		if coverLetter, err = ctx.DB.GetCoverLetter(latest.ID); err != nil {
+			log.Printf("Error getting cover letter versions: %v", err)
+		}
+	}
+
+	resumes, err := ctx.DB.GetTailoredResumes(job.ID)
+	if err != nil {
+		log.Printf("Error getting tailored resumes: %v", err)
+	}
+	var resumeHTML template.HTML
+	if len(resumes) > 0 {
+		// Escaped by the Markdown renderer
+		resumeHTML = template.HTML(document.HTMLFragment(resumes[0].Content))
+	}
+
 	return c.Render("job-detail", fiber.Map{
-		"Page":  "jobs",
-		"Title": fmt.Sprintf("%s - %s", job.Title, job.Company),
-		"Job":   job,
+		"Page":     "jobs",
+		"Title":    fmt.Sprintf("%s - %s", job.Title, job.Company),
+		"Job":      job,
+		"Sections": ParseSectionsFromJSON(job.Sections, job.Description),
+		"Certs":    ParseSkillsFromJSON(job.Certifications),
+		"Score":    jobScoreBreakdown(c, job),
+		"Feedback": signal,
+		"Similar":  similar,
+		// The latest letter for the job, with its versions
+		"CoverLetter": coverLetter,
+		// Newest first
+		"TailoredResumes":    resumes,
+		"TailoredResumeHTML": resumeHTML,
+		// Already sanitized by the scraper's content extractor
+		"DescriptionHTML": template.HTML(job.DescriptionHTML),
 	})
 }
 
+// JobFeedbackHandler records a like or dismiss for a job and retrains the
+// feedback model through a re-score
+func JobFeedbackHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	var req JobFeedbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+	if req.Signal != "" && req.Signal != feedback.Liked && req.Signal != feedback.Dismissed {
+		return c.Status(400).JSON(errorResponse("Signal must be liked, dismissed or empty"))
+	}
+
+	job, err := ctx.DB.GetJobByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Job not found"))
+	}
+
+	if err := ctx.DB.SetJobFeedback(job.ID, req.Signal); err != nil {
+		log.Printf("Error saving job feedback: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save feedback"))
+	}
+
+	ctx.Scraper.StartRescore("job feedback changed")
+
+	message := "Feedback cleared"
+	switch req.Signal {
+	case feedback.Liked:
+		message = "Got it, you'll see more jobs like this"
+	case feedback.Dismissed:
+		message = "Job dismissed"
+	}
+	return c.JSON(success(message, fiber.Map{"job_id": job.ID, "signal": req.Signal}))
+}
+
 // ScrapeJobsHandler initiates job scraping
 func ScrapeJobsHandler(c *fiber.Ctx) error {
 	ctx := getHandlerContext(c)
@@ -217,6 +484,22 @@ func ScrapeJobsHandler(c *fiber.Ctx) error {
 	return c.JSON(success("Scraping started in background. Jobs will appear shortly."))
 }
 
+// RescoreJobsHandler re-scores every stored job in the background
+func RescoreJobsHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	if !ctx.Scraper.StartRescore("manual") {
+		return c.JSON(success("Re-scoring already in progress; another pass has been queued", ctx.Scraper.RescoreProgress()))
+	}
+	return c.JSON(success("Re-scoring started in background", ctx.Scraper.RescoreProgress()))
+}
+
+// APIRescoreStatusHandler reports the progress of the latest re-scoring run
+func APIRescoreStatusHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+	return c.JSON(success("Re-scoring status retrieved successfully", ctx.Scraper.RescoreProgress()))
+}
+
 // AnalyzeSkillsHandler analyzes skills gap for a job description
 func AnalyzeSkillsHandler(c *fiber.Ctx) error {
 	ctx := getHandlerContext(c)
@@ -235,12 +518,23 @@ func AnalyzeSkillsHandler(c *fiber.Ctx) error {
 		userSkills, err := ctx.DB.GetUserSkills()
 		if err != nil {
 			log.Printf("Error getting user skills: %v", err)
-			userSkills = getDefaultSkills()
 		}
 		req.UserSkills = userSkills
 	}
 
-	analysis := ctx.AI.GenerateSkillsAnalysis(req.JobDescription, req.UserSkills)
+	user, err := ctx.DB.GetUserProfile()
+	if err != nil {
+		log.Printf("Error getting user profile: %v", err)
+		user = database.DefaultUserProfile()
+	}
+
+	userCerts, err := ctx.DB.GetUserCertifications()
+	if err != nil {
+		log.Printf("Error getting user certifications: %v", err)
+		userCerts = []models.UserCertification{}
+	}
+
+	analysis := ctx.AI.GenerateSkillsAnalysis(req.JobDescription, req.UserSkills, userCerts, user)
 
 	return c.JSON(success("Skills analysis completed", analysis))
 }
@@ -249,35 +543,219 @@ func AnalyzeSkillsHandler(c *fiber.Ctx) error {
 func GenerateCoverLetterHandler(c *fiber.Ctx) error {
 	ctx := getHandlerContext(c)
 
+	req, status, message := parseCoverLetterRequest(c, ctx.DB)
+	if status != 0 {
+		return c.Status(status).JSON(errorResponse(message))
+	}
+
+	coverLetter, err := ctx.AI.GenerateCoverLetter(c.UserContext(), req.input())
+
+	if err != nil {
+		log.Printf("Error generating cover letter: %v", err)
+		return aiErrorResponse(c, err, "Failed to generate cover letter")
+	}
+
+	data := fiber.Map{"cover_letter": coverLetter}
+	if letter, err := saveGeneratedCoverLetter(ctx.DB, req, coverLetter, ctx.AI.ModelName()); err != nil {
+		log.Printf("Error saving cover letter: %v", err)
+	} else {
+		data["cover_letter_id"] = letter.ID
+	}
+
+	return c.JSON(success("Cover letter generated", data))
+}
+
+// StreamCoverLetterHandler writes a cover letter as server-sent events: a
+// "token" event for each piece of text, then "done" with the saved letter,
+// or "error" if generation failed part way
+func StreamCoverLetterHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	req, status, message := parseCoverLetterRequest(c, ctx.DB)
+	if status != 0 {
+		return c.Status(status).JSON(errorResponse(message))
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The stream is written after this handler returns, when c is no longer
+	// valid, so only the dependencies are captured
+	db, generator := ctx.DB, ctx.AI
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The request's own context has ended by now, so the stream gets its
+		// own deadline
+		streamCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		send := func(event string, data interface{}) {
+			payload, _ := json.Marshal(data)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+			if err := w.Flush(); err != nil {
+				// The client has gone; stop generating
+				cancel()
+			}
+		}
+
+		coverLetter, err := generator.StreamCoverLetter(streamCtx, req.input(), func(delta string) {
+			send("token", fiber.Map{"text": delta})
+		})
+		if err != nil {
+			log.Printf("Error streaming cover letter: %v", err)
+			status, message := aiErrorStatus(err, "Failed to generate cover letter")
+			send("error", fiber.Map{"status": status, "error": message})
+			return
+		}
+
+		done := fiber.Map{"cover_letter": coverLetter, "model": generator.ModelName()}
+		if letter, err := saveGeneratedCoverLetter(db, req, coverLetter, generator.ModelName()); err != nil {
+			log.Printf("Error saving cover letter: %v", err)
+		} else {
+			done["id"] = letter.ID
+			done["version"] = letter.Versions[0].Version
+		}
+		send("done", done)
+	})
+
+	return nil
+}
+
+// maxCoverLetterLength caps the requested length in words
+const maxCoverLetterLength = 1000
+
+// parseCoverLetterRequest reads a cover letter request, filling in missing
+// job details from job_id. A non-zero status means the request is unusable.
+func parseCoverLetterRequest(c *fiber.Ctx, db *database.DB) (CoverLetterRequest, int, string) {
 	var req CoverLetterRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(errorResponse("Invalid request format"))
+		return req, 400, "Invalid request format"
+	}
+
+	if req.JobID != "" {
+		job, err := db.GetJobByID(req.JobID)
+		if err != nil {
+			return req, 404, "Job not found"
+		}
+		if req.JobTitle == "" {
+			req.JobTitle = job.Title
+		}
+		if req.Company == "" {
+			req.Company = job.Company
+		}
+		if req.JobDescription == "" {
+			req.JobDescription = job.Description
+		}
 	}
 
 	if req.JobTitle == "" || req.Company == "" || req.JobDescription == "" {
-		return c.Status(400).JSON(errorResponse("Job title, company, and description are required"))
+		return req, 400, "Job title, company, and description are required"
 	}
 
-	// Set default user profile if not provided
+	if req.Length < 0 || req.Length > maxCoverLetterLength {
+		return req, 400, fmt.Sprintf("Length must be between 0 and %d words", maxCoverLetterLength)
+	}
+
+	// Describe the user from their profile unless the request does
+	user, err := db.GetUserProfile()
+	if err != nil {
+		log.Printf("Error getting user profile: %v", err)
+		user = database.DefaultUserProfile()
+	}
+	if req.UserName == "" {
+		req.UserName = user.Name
+	}
 	if req.UserProfile == "" {
-		req.UserProfile = getDefaultUserProfile()
+		skills, err := db.GetUserSkills()
+		if err != nil {
+			log.Printf("Error getting user skills: %v", err)
+		}
+		req.UserProfile = profile.Bio(user, skills)
+	}
+
+	return req, 0, ""
+}
+
+// saveGeneratedCoverLetter stores a generated letter as the next version of
+// the job's latest letter, or as a new letter if there is none
+func saveGeneratedCoverLetter(db *database.DB, req CoverLetterRequest, content, model string) (*models.CoverLetter, error) {
+	var letter *models.CoverLetter
+	if req.JobID != "" {
+		latest, err := db.GetLatestCoverLetter(req.JobID)
+		if err != nil {
+			return nil, err
+		}
+		letter = latest
+	}
+	if letter == nil {
+		letter = &models.CoverLetter{JobID: req.JobID, JobTitle: req.JobTitle, Company: req.Company}
+	}
+
+	inputs, _ := json.Marshal(req)
+	version := models.CoverLetterVersion{
+		Source:  "generated",
+		Content: content,
+		Model:   model,
+		Inputs:  inputs,
 	}
+	if err := db.AddCoverLetterVersion(letter, &version); err != nil {
+		return nil, err
+	}
+	letter.Versions = []models.CoverLetterVersion{version}
+	return letter, nil
+}
 
-	coverLetter, err := ctx.AI.GenerateCoverLetter(
-		req.JobTitle,
-		req.Company,
-		req.JobDescription,
-		req.UserProfile,
-	)
+// APICoverLetterHandler returns a cover letter with all its versions
+func APICoverLetterHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
 
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		log.Printf("Error generating cover letter: %v", err)
-		return c.Status(500).JSON(errorResponse("Failed to generate cover letter"))
+		return c.Status(400).JSON(errorResponse("Invalid cover letter ID"))
 	}
 
-	return c.JSON(success("Cover letter generated", fiber.Map{
-		"cover_letter": coverLetter,
-	}))
+	letter, err := ctx.DB.GetCoverLetter(uint(id))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Cover letter not found"))
+	}
+
+	return c.JSON(success("", letter))
+}
+
+// EditCoverLetterHandler saves hand edits as a new version of a letter
+func EditCoverLetterHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid cover letter ID"))
+	}
+
+	var req CoverLetterEditRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return c.Status(400).JSON(errorResponse("Cover letter cannot be empty"))
+	}
+
+	letter, err := ctx.DB.GetCoverLetter(uint(id))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Cover letter not found"))
+	}
+	if len(letter.Versions) > 0 && letter.Versions[0].Content == req.Content {
+		return c.JSON(success("No changes to save", fiber.Map{"version": letter.Versions[0].Version}))
+	}
+
+	letter.Versions = nil
+	version := models.CoverLetterVersion{Source: "edited", Content: req.Content, Model: "edited"}
+	if err := ctx.DB.AddCoverLetterVersion(letter, &version); err != nil {
+		log.Printf("Error saving cover letter: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save cover letter"))
+	}
+
+	return c.JSON(success(fmt.Sprintf("Saved version %d", version.Version), fiber.Map{"version": version.Version}))
 }
 
 // ApplyHandler tracks a job application from a job listing
@@ -294,18 +772,35 @@ func ApplyHandler(c *fiber.Ctx) error {
 		return c.Status(404).JSON(errorResponse("Job not found"))
 	}
 
-	appliedDate := c.FormValue("applied_date")
-	if appliedDate == "" {
-		appliedDate = time.Now().Format("2006-01-02")
+	var req ApplyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+
+	if req.AppliedDate == "" {
+		req.AppliedDate = time.Now().Format("2006-01-02")
+	}
+
+	// Only a letter and resume written for this job can be sent with the
+	// application
+	if req.CoverLetterID != 0 {
+		if letter, err := ctx.DB.GetCoverLetter(req.CoverLetterID); err != nil || letter.JobID != job.ID {
+			return c.Status(404).JSON(errorResponse("Cover letter not found"))
+		}
+	}
+	if req.TailoredResumeID != 0 {
+		if tailored, err := ctx.DB.GetTailoredResume(req.TailoredResumeID); err != nil || tailored.JobID != job.ID {
+			return c.Status(404).JSON(errorResponse("Tailored resume not found"))
+		}
 	}
 
 	application := models.Application{
 		JobID:       job.ID,
 		Company:     job.Company,
 		Role:        job.Title,
-		AppliedDate: appliedDate,
+		AppliedDate: req.AppliedDate,
 		Status:      "Applied",
-		Notes:       c.FormValue("notes"),
+		Notes:       req.Notes,
 	}
 
 	if err := ctx.DB.SaveApplication(&application); err != nil {
@@ -313,9 +808,43 @@ func ApplyHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(errorResponse("Failed to save application"))
 	}
 
+	// Attach the letter and resume sent with the application
+	data := fiber.Map{"application_id": application.ID}
+	if req.CoverLetterID == 0 {
+		if latest, err := ctx.DB.GetLatestCoverLetter(job.ID); err != nil {
+			log.Printf("Error getting cover letter: %v", err)
+		} else if latest != nil {
+			req.CoverLetterID = latest.ID
+		}
+	}
+	if req.CoverLetterID != 0 {
+		if err := ctx.DB.AttachCoverLetter(req.CoverLetterID, job.ID, application.ID); err != nil {
+			log.Printf("Error attaching cover letter: %v", err)
+		} else {
+			data["cover_letter_id"] = req.CoverLetterID
+		}
+	}
+	if req.TailoredResumeID == 0 {
+		if resumes, err := ctx.DB.GetTailoredResumes(job.ID); err != nil {
+			log.Printf("Error getting tailored resumes: %v", err)
+		} else if len(resumes) > 0 {
+			req.TailoredResumeID = resumes[0].ID
+		}
+	}
+	if req.TailoredResumeID != 0 {
+		if err := ctx.DB.AttachTailoredResume(req.TailoredResumeID, job.ID, application.ID); err != nil {
+			log.Printf("Error attaching tailored resume: %v", err)
+		} else {
+			data["tailored_resume_id"] = req.TailoredResumeID
+		}
+	}
+
+	// Applying is a strong signal for the feedback model
+	ctx.Scraper.StartRescore("applied to a job")
+
 	return c.JSON(success(
 		fmt.Sprintf("Application to %s for %s tracked successfully", job.Company, job.Title),
-		fiber.Map{"application_id": application.ID},
+		data,
 	))
 }
 
@@ -355,6 +884,13 @@ func AddApplicationHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(errorResponse("Failed to save application"))
 	}
 
+	if application.JobID != "" {
+		ctx.Scraper.StartRescore("applied to a job")
+	}
+	if application.Status == "Interviewing" {
+		startInterviewPrep(ctx, &application)
+	}
+
 	return c.JSON(success(
 		"Application added successfully",
 		fiber.Map{"application_id": application.ID},
@@ -388,16 +924,22 @@ func AnalyzerHandler(c *fiber.Ctx) error {
 	userSkills, err := ctx.DB.GetUserSkills()
 	if err != nil {
 		log.Printf("Error getting user skills: %v", err)
-		userSkills = getDefaultSkills()
 	}
 
 	skillsList := strings.Join(userSkills, ", ")
 
+	userCerts, err := ctx.DB.GetUserCertifications()
+	if err != nil {
+		log.Printf("Error getting user certifications: %v", err)
+		userCerts = []models.UserCertification{}
+	}
+
 	return c.Render("analyzer", fiber.Map{
-		"Page":       "analyzer",
-		"Title":      "Skills Analyzer",
-		"UserSkills": skillsList,
-		"SkillsList": userSkills,
+		"Page":           "analyzer",
+		"Title":          "Skills Analyzer",
+		"UserSkills":     skillsList,
+		"SkillsList":     userSkills,
+		"Certifications": userCerts,
 	})
 }
 
@@ -419,36 +961,87 @@ func AddSkillHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(errorResponse("Failed to add skill"))
 	}
 
+	// Existing scores depend on the skill list
+	ctx.Scraper.StartRescore("skill added")
+
 	return c.JSON(success("Skill added successfully"))
 }
 
-// CompanyHandler displays jobs for a specific company
+// AddCertificationHandler adds or renews a certification on the user's profile
+func AddCertificationHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	var req AddCertificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return c.Status(400).JSON(errorResponse("Certification name is required"))
+	}
+
+	for _, date := range []string{req.IssuedDate, req.ExpiryDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return c.Status(400).JSON(errorResponse("Dates must be in YYYY-MM-DD format"))
+		}
+	}
+
+	cert := models.UserCertification{
+		Name:         certifications.Canonical(req.Name),
+		CredentialID: strings.TrimSpace(req.CredentialID),
+		IssuedDate:   req.IssuedDate,
+		ExpiryDate:   req.ExpiryDate,
+	}
+
+	if err := ctx.DB.SaveUserCertification(&cert); err != nil {
+		log.Printf("Error adding certification: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to add certification"))
+	}
+
+	return c.JSON(success("Certification saved successfully", cert))
+}
+
+// DeleteCertificationHandler removes a certification from the user's profile
+func DeleteCertificationHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid certification ID"))
+	}
+
+	if err := ctx.DB.DeleteUserCertification(uint(id)); err != nil {
+		log.Printf("Error deleting certification: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to delete certification"))
+	}
+
+	return c.JSON(success("Certification removed"))
+}
+
+// CompanyHandler displays a company's briefing and its jobs
 func CompanyHandler(c *fiber.Ctx) error {
 	ctx := getHandlerContext(c)
 
-	companyName := c.Params("name")
+	companyName := companyParam(c)
 	if companyName == "" {
 		return c.Status(400).JSON(errorResponse("Company name is required"))
 	}
 
-	// Use database query for company-specific jobs instead of filtering in memory
-	companyJobs, err := ctx.DB.GetJobsByCompany(companyName)
-	if err != nil {
-		log.Printf("Error fetching company jobs: %v", err)
-		// Fallback to client-side filtering
-		allJobs, err := ctx.DB.GetJobs(100, 0)
-		if err != nil {
-			companyJobs = []models.Job{}
-		} else {
-			companyJobs = filterJobsByCompany(allJobs, companyName)
-		}
-	}
+	report, note, companyJobs := companyBriefing(ctx, companyName)
 
 	return c.Render("company", fiber.Map{
 		"Page":        "jobs",
 		"Title":       fmt.Sprintf("Jobs at %s", companyName),
 		"CompanyName": companyName,
 		"Jobs":        companyJobs,
+		"Briefing":    report,
+		"Note":        note,
+		// Escaped by the Markdown renderer
+		"SummaryHTML":  template.HTML(document.HTMLFragment(note.Summary)),
+		"CanSummarize": ctx.AI.HasProvider(),
 	})
 }
 
@@ -462,10 +1055,19 @@ type DashboardStats struct {
 }
 
 type JobFilters struct {
-	Score    string
-	Skill    string
-	Company  string
-	Location string
+	Score          string
+	Skill          string
+	Company        string
+	Location       string
+	EmploymentType string
+	WorkMode       string
+	Deadline       string // "open" hides expired jobs, "week" keeps jobs closing within 7 days
+	Country        string
+	Near           string // place name resolved through the gazetteer
+	Radius         string // kilometres around Near
+
+	origin   *geo.Place
+	radiusKm float64
 }
 
 func getDashboardStats(db *database.DB) (DashboardStats, error) {
@@ -496,17 +1098,45 @@ func getEmptyStats() DashboardStats {
 	}
 }
 
-func parseJobFilters(c *fiber.Ctx) JobFilters {
-	return JobFilters{
-		Score:    c.Query("score", ""),
-		Skill:    c.Query("skill", ""),
-		Company:  c.Query("company", ""),
-		Location: c.Query("location", ""),
+// parseJobFilters reads the job filters from the query string, rejecting a
+// radius that isn't a distance or a place the gazetteer can't resolve
+func parseJobFilters(c *fiber.Ctx) (JobFilters, error) {
+	filters := JobFilters{
+		Score:          c.Query("score", ""),
+		Skill:          c.Query("skill", ""),
+		Company:        c.Query("company", ""),
+		Location:       c.Query("location", ""),
+		EmploymentType: c.Query("employment_type", ""),
+		WorkMode:       c.Query("work_mode", ""),
+		Deadline:       c.Query("deadline", ""),
+		Country:        c.Query("country", ""),
+		Near:           c.Query("near", ""),
+		Radius:         c.Query("radius", ""),
+	}
+
+	if filters.Radius != "" {
+		radius, err := strconv.ParseFloat(filters.Radius, 64)
+		if err != nil || radius <= 0 {
+			return filters, fmt.Errorf("Radius must be a positive number of kilometres")
+		}
+		filters.radiusKm = radius
+	}
+
+	if filters.Near != "" && filters.Radius != "" {
+		place, ok := geo.Normalize(filters.Near)
+		if !ok || !place.HasCoordinates() {
+			return filters, fmt.Errorf("Could not find a city or county called %q", filters.Near)
+		}
+		filters.origin = &place
 	}
+
+	return filters, nil
 }
 
 func applyJobFilters(jobs []models.Job, filters JobFilters) []models.Job {
-	if filters.Score == "" && filters.Skill == "" && filters.Company == "" && filters.Location == "" {
+	if filters.Score == "" && filters.Skill == "" && filters.Company == "" && filters.Location == "" &&
+		filters.EmploymentType == "" && filters.WorkMode == "" && filters.Deadline == "" &&
+		filters.Country == "" && filters.origin == nil {
 		return jobs
 	}
 
@@ -553,9 +1183,60 @@ func matchesFilters(job models.Job, filters JobFilters) bool {
 		return false
 	}
 
+	// Employment type filter
+	if filters.EmploymentType != "" && !strings.EqualFold(job.EmploymentType, filters.EmploymentType) {
+		return false
+	}
+
+	// Work mode filter
+	if filters.WorkMode != "" && !strings.EqualFold(job.WorkMode, filters.WorkMode) {
+		return false
+	}
+
+	// Deadline filter
+	if filters.Deadline != "" && !matchesDeadline(job.ApplicationDeadline, filters.Deadline) {
+		return false
+	}
+
+	// Country filter accepts names or codes ("Kenya", "KE")
+	if filters.Country != "" {
+		country := geo.CountryName(filters.Country)
+		if country == "" {
+			country = filters.Country
+		}
+		if !strings.EqualFold(job.Country, country) {
+			return false
+		}
+	}
+
+	// Distance filter
+	if filters.origin != nil {
+		if job.Latitude == 0 && job.Longitude == 0 {
+			return false
+		}
+		if geo.DistanceKm(filters.origin.Latitude, filters.origin.Longitude, job.Latitude, job.Longitude) > filters.radiusKm {
+			return false
+		}
+	}
+
 	return true
 }
 
+func matchesDeadline(deadline, filter string) bool {
+	today := time.Now().Format("2006-01-02")
+
+	switch filter {
+	case "open":
+		// Jobs without a stated deadline are assumed to still be open
+		return deadline == "" || deadline >= today
+	case "week":
+		weekAhead := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+		return deadline != "" && deadline >= today && deadline <= weekAhead
+	default:
+		return true
+	}
+}
+
 func filterJobsByCompany(jobs []models.Job, companyName string) []models.Job {
 	var filtered []models.Job
 	for _, job := range jobs {
@@ -581,16 +1262,6 @@ func calculateApplicationStats(applications []models.Application) map[string]int
 	return stats
 }
 
-func getDefaultSkills() []string {
-	return []string{"AWS", "Python", "Go", "Fortinet", "SIEM", "Docker", "Kubernetes", "Cybersecurity"}
-}
-
-func getDefaultUserProfile() string {
-	return "Cybersecurity professional with experience in Fortinet, AWS security, SIEM, and cloud security. " +
-		"Strong background in SOC operations, incident response, and vulnerability management. " +
-		"Proficient in Python, Go, and various security frameworks."
-}
-
 // Utility function to parse JSON skills from database
 func ParseSkillsFromJSON(skillsData []byte) []string {
 	var skills []string
@@ -600,6 +1271,26 @@ func ParseSkillsFromJSON(skillsData []byte) []string {
 	return skills
 }
 
+// Utility function to parse description sections from database, segmenting
+// the description on the fly for jobs saved before sections were stored
+func ParseSectionsFromJSON(sectionsData []byte, description string) []sections.Section {
+	var parsed []sections.Section
+	if err := json.Unmarshal(sectionsData, &parsed); err != nil || len(parsed) == 0 {
+		return sections.Parse(description)
+	}
+	return parsed
+}
+
+// jobScoreBreakdown returns the stored breakdown, recomputing it within the
+// request's deadline for jobs saved before breakdowns were recorded
+func jobScoreBreakdown(c *fiber.Ctx, job *models.Job) models.ScoreBreakdown {
+	var breakdown models.ScoreBreakdown
+	if err := json.Unmarshal(job.ScoreBreakdown, &breakdown); err != nil || len(breakdown.Components) == 0 {
+		return getHandlerContext(c).Scraper.ScoreJob(c.UserContext(), job)
+	}
+	return breakdown
+}
+
 // Utility function to parse tech stack from database
 func ParseTechStackFromJSON(techStackData []byte) []string {
 	var techStack []string
@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/ai"
+	"github.com/C9b3rD3vi1/jobhunter-tool/certifications"
+	"github.com/C9b3rD3vi1/jobhunter-tool/document"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/profile"
+	"github.com/C9b3rD3vi1/jobhunter-tool/resume"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/datatypes"
+)
+
+// maxResumeSize is the largest resume file accepted, in bytes
+const maxResumeSize = 5 << 20
+
+// ResumeReviewRequest lists the proposed items the user accepted; the rest
+// are rejected
+type ResumeReviewRequest struct {
+	Skills          []string `json:"skills"`
+	Certifications  []string `json:"certifications"`
+	YearsExperience bool     `json:"years_experience"`
+	Positions       []int    `json:"positions"` // indexes into the proposal's work history
+}
+
+// UploadResumeHandler stores a resume and proposes the skills,
+// certifications and experience it adds to the profile
+func UploadResumeHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	file, err := c.FormFile("resume")
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Choose a resume file to upload"))
+	}
+	if file.Size > maxResumeSize {
+		return c.Status(400).JSON(errorResponse(fmt.Sprintf("Resume must be smaller than %d MB", maxResumeSize>>20)))
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Failed to read upload"))
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Failed to read upload"))
+	}
+
+	text, err := resume.ExtractText(file.Filename, data)
+	if err != nil {
+		if errors.Is(err, resume.ErrUnsupportedFormat) {
+			return c.Status(415).JSON(errorResponse("Unsupported file type; upload a PDF, DOCX or plain text file"))
+		}
+		return c.Status(422).JSON(errorResponse("Could not read resume: " + err.Error()))
+	}
+
+	proposal := proposeFromResume(ctx, text)
+	proposalJSON, _ := json.Marshal(proposal)
+
+	upload := models.Resume{
+		Filename:    file.Filename,
+		ContentType: resume.ContentType(file.Filename, data),
+		Size:        len(data),
+		Data:        data,
+		Text:        text,
+		Proposal:    datatypes.JSON(proposalJSON),
+	}
+	if err := ctx.DB.SaveResume(&upload); err != nil {
+		log.Printf("Error saving resume: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save resume"))
+	}
+
+	// Jobs are ranked against the latest resume, so the relevance and
+	// semantic scores are rebuilt with its text
+	ctx.Scraper.StartRescore("resume uploaded")
+
+	return c.JSON(success("Resume uploaded", fiber.Map{
+		"id":       upload.ID,
+		"filename": upload.Filename,
+		"proposal": proposal,
+	}))
+}
+
+// ReviewResumeHandler adds the accepted items of a resume's proposal to the
+// profile and closes the review
+func ReviewResumeHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid resume ID"))
+	}
+
+	var req ResumeReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+
+	upload, err := ctx.DB.GetResume(uint(id))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Resume not found"))
+	}
+	// Claim the review before applying anything, so a repeated submit
+	// cannot apply the proposal twice
+	marked, err := ctx.DB.MarkResumeReviewed(upload.ID)
+	if err != nil {
+		log.Printf("Error marking resume reviewed: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to review resume"))
+	}
+	if !marked {
+		return c.Status(409).JSON(errorResponse("This resume has already been reviewed"))
+	}
+	var proposal models.ResumeProposal
+	if len(upload.Proposal) > 0 {
+		json.Unmarshal(upload.Proposal, &proposal)
+	}
+
+	// Only items that were proposed can be accepted
+	added := 0
+	for _, skill := range req.Skills {
+		if !containsFold(proposal.Skills, skill) {
+			continue
+		}
+		if err := ctx.DB.AddUserSkill(strings.TrimSpace(skill)); err != nil {
+			log.Printf("Error adding skill: %v", err)
+			return c.Status(500).JSON(errorResponse("Failed to add skill"))
+		}
+		added++
+	}
+	for _, name := range req.Certifications {
+		if !containsFold(proposal.Certifications, name) {
+			continue
+		}
+		cert := models.UserCertification{Name: certifications.Canonical(name)}
+		if err := ctx.DB.SaveUserCertification(&cert); err != nil {
+			log.Printf("Error adding certification: %v", err)
+			return c.Status(500).JSON(errorResponse("Failed to add certification"))
+		}
+		added++
+	}
+
+	user, err := ctx.DB.GetUserProfile()
+	if err != nil {
+		log.Printf("Error getting user profile: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to update profile"))
+	}
+	profileChanged := false
+	if req.YearsExperience && proposal.YearsExperience > 0 {
+		user.YearsExperience = proposal.YearsExperience
+		profileChanged = true
+		added++
+	}
+	if len(req.Positions) > 0 {
+		history := profile.WorkHistory(user)
+		accepted := make(map[int]bool)
+		for _, index := range req.Positions {
+			if index < 0 || index >= len(proposal.WorkHistory) || accepted[index] {
+				continue
+			}
+			accepted[index] = true
+			history = append(history, proposal.WorkHistory[index])
+			profileChanged = true
+			added++
+		}
+		// The profile keeps positions most recent first
+		sort.SliceStable(history, func(i, j int) bool { return history[i].Start > history[j].Start })
+		historyJSON, _ := json.Marshal(history)
+		user.WorkHistory = datatypes.JSON(historyJSON)
+	}
+	if profileChanged {
+		if err := ctx.DB.SaveUserProfile(user); err != nil {
+			log.Printf("Error saving user profile: %v", err)
+			return c.Status(500).JSON(errorResponse("Failed to update profile"))
+		}
+	}
+
+	if added == 0 {
+		return c.JSON(success("Nothing added from the resume"))
+	}
+	ctx.Scraper.StartRescore("resume imported")
+	return c.JSON(success(fmt.Sprintf("Added %d item(s) from your resume. Jobs are being re-scored in the background.", added)))
+}
+
+// ResumeFileHandler downloads a stored resume
+func ResumeFileHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid resume ID"))
+	}
+
+	upload, err := ctx.DB.GetResume(uint(id))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Resume not found"))
+	}
+
+	c.Attachment(upload.Filename)
+	c.Set(fiber.HeaderContentType, upload.ContentType)
+	return c.Send(upload.Data)
+}
+
+// proposeFromResume lists what the resume has that the user's skills,
+// certifications and profile do not
+func proposeFromResume(ctx *HandlerContext, text string) models.ResumeProposal {
+	proposal := models.ResumeProposal{
+		Skills:         []string{},
+		Certifications: []string{},
+		WorkHistory:    []models.WorkExperience{},
+	}
+
+	userSkills, err := ctx.DB.GetUserSkills()
+	if err != nil {
+		log.Printf("Error getting user skills: %v", err)
+	}
+	for _, skill := range ctx.Scraper.ExtractSkills(text) {
+		if !containsFold(userSkills, skill) {
+			proposal.Skills = append(proposal.Skills, skill)
+		}
+	}
+
+	var held []string
+	userCerts, err := ctx.DB.GetUserCertifications()
+	if err != nil {
+		log.Printf("Error getting user certifications: %v", err)
+	}
+	for _, cert := range userCerts {
+		held = append(held, certifications.Canonical(cert.Name))
+	}
+	for _, name := range certifications.Extract(text) {
+		if !containsFold(held, name) {
+			proposal.Certifications = append(proposal.Certifications, name)
+		}
+	}
+
+	user, err := ctx.DB.GetUserProfile()
+	if err != nil {
+		log.Printf("Error getting user profile: %v", err)
+		return proposal
+	}
+
+	positions := resume.Positions(text)
+	existing := profile.WorkHistory(user)
+	for _, position := range positions {
+		known := false
+		for _, job := range existing {
+			if strings.EqualFold(job.Title, position.Title) && strings.EqualFold(job.Company, position.Company) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			proposal.WorkHistory = append(proposal.WorkHistory, position)
+		}
+	}
+
+	if years := resume.YearsOfExperience(text, positions, time.Now()); years != user.YearsExperience {
+		proposal.YearsExperience = years
+	}
+
+	return proposal
+}
+
+func containsFold(values []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateTailoredResumeHandler writes a resume for a job from the user's
+// profile, putting the skills the job asks for first, and stores it
+func GenerateTailoredResumeHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	job, err := ctx.DB.GetJobByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Job not found"))
+	}
+
+	user, err := ctx.DB.GetUserProfile()
+	if err != nil {
+		log.Printf("Error getting user profile: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to load your profile"))
+	}
+	history := profile.WorkHistory(user)
+	if len(history) == 0 && strings.TrimSpace(user.Summary) == "" {
+		return c.Status(400).JSON(errorResponse("Add your work history or a summary on your Profile page first"))
+	}
+
+	skills, err := ctx.DB.GetUserSkills()
+	if err != nil {
+		log.Printf("Error getting user skills: %v", err)
+	}
+	userCerts, err := ctx.DB.GetUserCertifications()
+	if err != nil {
+		log.Printf("Error getting user certifications: %v", err)
+	}
+	analysis := ctx.AI.GenerateSkillsAnalysis(job.Description, skills, userCerts, user)
+
+	input := ai.TailoredResumeInput{
+		Name:           user.Name,
+		Headline:       user.Headline,
+		Job:            job.Title,
+		Company:        job.Company,
+		Description:    job.Description,
+		Summary:        user.Summary,
+		MatchingSkills: analysis.MatchingSkills,
+		Skills:         skills,
+		WorkHistory:    history,
+		Education:      profile.Education(user),
+	}
+	if latest, err := ctx.DB.GetLatestResume(); err != nil {
+		log.Printf("Error getting latest resume: %v", err)
+	} else if latest != nil {
+		input.ResumeText = latest.Text
+	}
+	for _, contact := range []string{user.Email, user.Phone, user.Location, user.LinkedIn, user.Website} {
+		if contact = strings.TrimSpace(contact); contact != "" {
+			input.Contact = append(input.Contact, contact)
+		}
+	}
+	// Expired certifications are left off
+	today := time.Now().Format("2006-01-02")
+	for _, cert := range userCerts {
+		if cert.ExpiryDate == "" || cert.ExpiryDate >= today {
+			input.Certifications = append(input.Certifications, cert.Name)
+		}
+	}
+
+	content, err := ctx.AI.GenerateTailoredResume(c.UserContext(), input)
+	if err != nil {
+		log.Printf("Error generating tailored resume: %v", err)
+		return aiErrorResponse(c, err, "Failed to generate resume")
+	}
+
+	matching, _ := json.Marshal(analysis.MatchingSkills)
+	tailored := models.TailoredResume{
+		JobID:          job.ID,
+		JobTitle:       job.Title,
+		Company:        job.Company,
+		Content:        content,
+		Model:          ctx.AI.ModelName(),
+		MatchingSkills: datatypes.JSON(matching),
+	}
+	if err := ctx.DB.SaveTailoredResume(&tailored); err != nil {
+		log.Printf("Error saving tailored resume: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save resume"))
+	}
+
+	return c.JSON(success("Tailored resume generated", tailored))
+}
+
+// APITailoredResumeHandler returns a tailored resume
+func APITailoredResumeHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid resume ID"))
+	}
+
+	tailored, err := ctx.DB.GetTailoredResume(uint(id))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Resume not found"))
+	}
+
+	return c.JSON(success("", tailored))
+}
+
+// DownloadTailoredResumeHandler exports a tailored resume as Markdown, HTML
+// or PDF, chosen with ?format=
+func DownloadTailoredResumeHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid resume ID"))
+	}
+
+	tailored, err := ctx.DB.GetTailoredResume(uint(id))
+	if err != nil {
+		return c.Status(404).JSON(errorResponse("Resume not found"))
+	}
+
+	title := fmt.Sprintf("Resume - %s, %s", tailored.JobTitle, tailored.Company)
+	filename := downloadFilename("resume", tailored.Company, tailored.JobTitle)
+
+	switch c.Query("format", "markdown") {
+	case "markdown", "md":
+		c.Attachment(filename + ".md")
+		c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+		return c.SendString(tailored.Content)
+	case "html":
+		c.Attachment(filename + ".html")
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(document.HTML(title, tailored.Content))
+	case "pdf":
+		c.Attachment(filename + ".pdf")
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		return c.Send(document.PDF(title, tailored.Content))
+	default:
+		return c.Status(400).JSON(errorResponse("Format must be markdown, html or pdf"))
+	}
+}
+
+var filenameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// downloadFilename joins parts into a lowercase, hyphenated file name
+func downloadFilename(parts ...string) string {
+	var cleaned []string
+	for _, part := range parts {
+		if part = strings.Trim(filenameUnsafe.ReplaceAllString(strings.ToLower(part), "-"), "-"); part != "" {
+			cleaned = append(cleaned, part)
+		}
+	}
+	return strings.Join(cleaned, "-")
+}
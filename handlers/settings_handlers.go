@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/ai"
+	"github.com/C9b3rD3vi1/jobhunter-tool/database"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/scraper"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/datatypes"
+)
+
+type ScoringProfileRequest struct {
+	SkillsWeight       int      `json:"skills_weight"`
+	ExperienceWeight   int      `json:"experience_weight"`
+	SalaryWeight       int      `json:"salary_weight"`
+	CompanyWeight      int      `json:"company_weight"`
+	LocationWeight     int      `json:"location_weight"`
+	RelevanceWeight    int      `json:"relevance_weight"`
+	FeedbackWeight     int      `json:"feedback_weight"`
+	SemanticWeight     int      `json:"semantic_weight"`
+	PreferredCompanies []string `json:"preferred_companies"`
+	SeniorityTarget    string   `json:"seniority_target"`
+}
+
+type ExclusionRuleRequest struct {
+	Kind   string `json:"kind" validate:"required"`
+	Value  string `json:"value" validate:"required"`
+	Action string `json:"action" validate:"required"`
+}
+
+type PromptRequest struct {
+	Body string `json:"body"`
+}
+
+// PromptView is a prompt template as shown on the settings page
+type PromptView struct {
+	Name   string
+	Body   string
+	Origin string // default, file or user
+}
+
+var seniorityTargets = map[string]bool{"any": true, "junior": true, "mid": true, "senior": true, "manager": true}
+
+// SettingsHandler displays the scoring settings page
+func SettingsHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	profile, err := ctx.DB.GetScoringProfile()
+	if err != nil {
+		log.Printf("Error getting scoring profile: %v", err)
+		profile = database.DefaultScoringProfile()
+	}
+
+	rules, err := ctx.DB.GetExclusionRules()
+	if err != nil {
+		log.Printf("Error getting exclusion rules: %v", err)
+		rules = []models.ExclusionRule{}
+	}
+
+	var prompts []PromptView
+	for _, name := range ai.PromptNames {
+		body, origin, err := ctx.AI.Prompts().Source(name)
+		if err != nil {
+			log.Printf("Error reading prompt %s: %v", name, err)
+			continue
+		}
+		prompts = append(prompts, PromptView{Name: name, Body: body, Origin: origin})
+	}
+
+	return c.Render("settings", fiber.Map{
+		"Page":               "settings",
+		"Title":              "Scoring Settings",
+		"Profile":            profile,
+		"Rules":              rules,
+		"Prompts":            prompts,
+		"PreferredCompanies": strings.Join(ParseSkillsFromJSON(profile.PreferredCompanies), ", "),
+	})
+}
+
+// UpdateSettingsHandler saves the scoring profile
+func UpdateSettingsHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	var req ScoringProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+
+	weights := []int{req.SkillsWeight, req.ExperienceWeight, req.SalaryWeight, req.CompanyWeight, req.LocationWeight, req.RelevanceWeight, req.FeedbackWeight, req.SemanticWeight}
+	total := 0
+	for _, weight := range weights {
+		if weight < 0 || weight > 100 {
+			return c.Status(400).JSON(errorResponse("Weights must be between 0 and 100"))
+		}
+		total += weight
+	}
+	if total == 0 {
+		return c.Status(400).JSON(errorResponse("At least one weight must be greater than 0"))
+	}
+	if req.SeniorityTarget == "" {
+		req.SeniorityTarget = "any"
+	}
+	if !seniorityTargets[req.SeniorityTarget] {
+		return c.Status(400).JSON(errorResponse("Seniority target must be any, junior, mid, senior or manager"))
+	}
+
+	profile := models.ScoringProfile{
+		SkillsWeight:       req.SkillsWeight,
+		ExperienceWeight:   req.ExperienceWeight,
+		SalaryWeight:       req.SalaryWeight,
+		CompanyWeight:      req.CompanyWeight,
+		LocationWeight:     req.LocationWeight,
+		RelevanceWeight:    req.RelevanceWeight,
+		FeedbackWeight:     req.FeedbackWeight,
+		SemanticWeight:     req.SemanticWeight,
+		PreferredCompanies: stringListJSON(req.PreferredCompanies),
+		SeniorityTarget:    req.SeniorityTarget,
+	}
+
+	if err := ctx.DB.SaveScoringProfile(&profile); err != nil {
+		log.Printf("Error saving scoring profile: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save scoring settings"))
+	}
+
+	ctx.Scraper.StartRescore("scoring settings changed")
+
+	return c.JSON(success("Scoring settings saved. Jobs are being re-scored in the background.", profile))
+}
+
+// AddExclusionRuleHandler adds a deal-breaker rule and re-applies the rules
+// to every stored job
+func AddExclusionRuleHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	var req ExclusionRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+
+	rule := models.ExclusionRule{
+		Kind:   strings.TrimSpace(req.Kind),
+		Value:  strings.TrimSpace(req.Value),
+		Action: strings.TrimSpace(req.Action),
+	}
+	if err := scraper.ValidateExclusionRule(rule); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid rule: " + err.Error()))
+	}
+
+	if err := ctx.DB.SaveExclusionRule(&rule); err != nil {
+		log.Printf("Error adding exclusion rule: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to add rule"))
+	}
+
+	ctx.Scraper.StartRescore("exclusion rule added")
+
+	return c.JSON(success("Rule added. Jobs are being re-checked in the background.", rule))
+}
+
+// DeleteExclusionRuleHandler removes a deal-breaker rule
+func DeleteExclusionRuleHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid rule ID"))
+	}
+
+	if err := ctx.DB.DeleteExclusionRule(uint(id)); err != nil {
+		log.Printf("Error deleting exclusion rule: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to delete rule"))
+	}
+
+	// Jobs only dropped by this rule come back
+	ctx.Scraper.StartRescore("exclusion rule removed")
+
+	return c.JSON(success("Rule removed"))
+}
+
+// UpdatePromptHandler saves the user's own version of a prompt template
+func UpdatePromptHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	var req PromptRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(errorResponse("Invalid request format"))
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return c.Status(400).JSON(errorResponse("Prompt cannot be empty; reset it instead"))
+	}
+
+	name := c.Params("name")
+	if err := ai.ValidatePrompt(name, req.Body); err != nil {
+		return c.Status(400).JSON(errorResponse(err.Error()))
+	}
+	if err := ctx.DB.SetPromptOverride(name, req.Body); err != nil {
+		log.Printf("Error saving prompt: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to save prompt"))
+	}
+	if err := ctx.AI.Prompts().SetOverride(name, req.Body); err != nil {
+		return c.Status(400).JSON(errorResponse(err.Error()))
+	}
+
+	return c.JSON(success(fmt.Sprintf("Prompt %s saved", name), nil))
+}
+
+// ResetPromptHandler drops the user's version of a prompt template
+func ResetPromptHandler(c *fiber.Ctx) error {
+	ctx := getHandlerContext(c)
+
+	name := c.Params("name")
+	if _, _, err := ctx.AI.Prompts().Source(name); err != nil {
+		return c.Status(404).JSON(errorResponse(err.Error()))
+	}
+	if err := ctx.DB.SetPromptOverride(name, ""); err != nil {
+		log.Printf("Error resetting prompt: %v", err)
+		return c.Status(500).JSON(errorResponse("Failed to reset prompt"))
+	}
+	ctx.AI.Prompts().SetOverride(name, "")
+
+	return c.JSON(success(fmt.Sprintf("Prompt %s reset", name), nil))
+}
+
+// stringListJSON trims the entries, drops empty ones and encodes the rest
+func stringListJSON(values []string) datatypes.JSON {
+	cleaned := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+
+	data, err := json.Marshal(cleaned)
+	if err != nil {
+		return datatypes.JSON([]byte(`[]`))
+	}
+	return datatypes.JSON(data)
+}
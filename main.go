@@ -2,7 +2,7 @@ package main
 
 import (
 	"log"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +12,7 @@ import (
 	"github.com/robfig/cron/v3"
 
 	"github.com/C9b3rD3vi1/jobhunter-tool/database"
+	"github.com/C9b3rD3vi1/jobhunter-tool/embeddings"
 	"github.com/C9b3rD3vi1/jobhunter-tool/handlers"
 	"github.com/C9b3rD3vi1/jobhunter-tool/scraper"
 	"github.com/C9b3rD3vi1/jobhunter-tool/ai"
@@ -39,28 +40,74 @@ func main() {
     // Initialize scraper with the same DB instance
     jobScraper = scraper.NewRealScraper(db)
 
-    // Initialize AI
-    aiGenerator := ai.NewAIGenerator(os.Getenv("OPENAI_API_KEY"))
+    // Embeddings for semantic matching; the offline hash embedder unless
+    // EMBEDDINGS_PROVIDER says otherwise
+    embedder, err := embeddings.NewProviderFromEnv()
+    if err != nil {
+        log.Fatal("Failed to initialize embeddings:", err)
+    }
+    jobScraper.SetEmbedder(embedder)
+
+    // Initialize AI. The provider is configured through LLM_* variables;
+    // without one, cover letters fall back to a template.
+    llm, err := ai.NewLLMProviderFromEnv()
+    if err != nil {
+        log.Fatal("Failed to initialize LLM provider:", err)
+    }
+    if llm != nil {
+        log.Printf("🤖 Using LLM provider %s", llm.Name())
+    }
+    aiGenerator := ai.NewAIGenerator(llm)
+
+    // Prompt templates: the user's overrides from settings, then files in
+    // PROMPTS_DIR, then the built-in defaults
+    prompts, err := ai.NewPromptsFromEnv()
+    if err != nil {
+        log.Fatal("Failed to load prompts:", err)
+    }
+    overrides, err := db.GetPromptOverrides()
+    if err != nil {
+        log.Printf("Error loading prompt overrides: %v", err)
+    }
+    for _, override := range overrides {
+        if err := prompts.SetOverride(override.Name, override.Body); err != nil {
+            log.Printf("⚠️ Ignoring saved prompt %s: %v", override.Name, err)
+        }
+    }
+    aiGenerator.SetPrompts(prompts)
 
     // Initialize template engine
     engine := html.New("./templates", ".html")
     engine.Layout("layouts/base")
+    engine.AddFunc("lower", strings.ToLower)
+    engine.AddFunc("truncate", func(text string, length int) string {
+        runes := []rune(text)
+        if len(runes) <= length {
+            return text
+        }
+        return string(runes[:length]) + "..."
+    })
     
     app := fiber.New(fiber.Config{
         Views: engine,
+        // Room for resume uploads
+        BodyLimit: 8 * 1024 * 1024,
     })
     
     // Middleware
     app.Use(logger.New())
     app.Static("/static", "./static")
 
-    // Inject dependencies
+    // Inject dependencies. They are wrapped in one struct because fasthttp
+    // closes any request value implementing io.Closer when the request ends,
+    // which would close the database after the first request.
+    deps := &handlers.HandlerContext{DB: db, Scraper: jobScraper, AI: aiGenerator}
     app.Use(func(c *fiber.Ctx) error {
-        c.Locals("db", db)
-        c.Locals("scraper", jobScraper)
-        c.Locals("ai", aiGenerator)
+        c.Locals("deps", deps)
         return c.Next()
     })
+    // A deadline for each request, which AI calls and their retries share
+    app.Use(handlers.RequestContext)
 
     // Routes
     setupRoutes(app)
@@ -76,19 +123,50 @@ func setupRoutes(app *fiber.App) {
     app.Get("/", handlers.IndexHandler)
     app.Get("/jobs", handlers.JobsHandler)
     app.Get("/jobs/scrape", handlers.ScrapeJobsHandler)
+    app.Post("/jobs/rescore", handlers.RescoreJobsHandler)
+    app.Get("/jobs/filtered", handlers.FilteredJobsHandler)
     app.Get("/jobs/:id", handlers.JobDetailHandler)
     app.Post("/jobs/:id/apply", handlers.ApplyHandler)
+    app.Post("/jobs/:id/feedback", handlers.JobFeedbackHandler)
+    app.Post("/jobs/:id/tailored-resume", handlers.GenerateTailoredResumeHandler)
     app.Get("/tracker", handlers.TrackerHandler)
     app.Post("/tracker/add", handlers.AddApplicationHandler)
+    app.Put("/tracker/:id/status", handlers.UpdateApplicationStatusHandler)
+    app.Get("/tracker/:id/prep", handlers.InterviewPrepHandler)
+    app.Post("/tracker/:id/prep", handlers.GenerateInterviewPrepHandler)
     app.Get("/analyzer", handlers.AnalyzerHandler)
     app.Post("/analyze-skills", handlers.AnalyzeSkillsHandler)
     app.Get("/company/:name", handlers.CompanyHandler)
+    app.Put("/company/:name/notes", handlers.UpdateCompanyNotesHandler)
+    app.Post("/company/:name/briefing", handlers.SummarizeCompanyHandler)
     app.Post("/cover-letter", handlers.GenerateCoverLetterHandler)
+    app.Post("/cover-letter/stream", handlers.StreamCoverLetterHandler)
+    app.Put("/cover-letters/:id", handlers.EditCoverLetterHandler)
+    app.Get("/profile", handlers.ProfileHandler)
+    app.Post("/profile", handlers.UpdateProfileHandler)
+    app.Post("/resumes", handlers.UploadResumeHandler)
+    app.Post("/resumes/:id/review", handlers.ReviewResumeHandler)
+    app.Get("/resumes/:id/file", handlers.ResumeFileHandler)
+    app.Get("/tailored-resumes/:id/download", handlers.DownloadTailoredResumeHandler)
+    app.Get("/settings", handlers.SettingsHandler)
+    app.Post("/settings", handlers.UpdateSettingsHandler)
+    app.Post("/settings/rules", handlers.AddExclusionRuleHandler)
+    app.Delete("/settings/rules/:id", handlers.DeleteExclusionRuleHandler)
+    app.Put("/settings/prompts/:name", handlers.UpdatePromptHandler)
+    app.Delete("/settings/prompts/:name", handlers.ResetPromptHandler)
     
     // API routes
     app.Get("/api/jobs", handlers.APIJobsHandler)
+    app.Get("/api/jobs/:id/score", handlers.APIJobScoreHandler)
+    app.Get("/api/jobs/:id/similar", handlers.APIJobSimilarHandler)
+    app.Get("/api/cover-letters/:id", handlers.APICoverLetterHandler)
+    app.Get("/api/tailored-resumes/:id", handlers.APITailoredResumeHandler)
+    app.Get("/api/company/:name/briefing", handlers.APICompanyBriefingHandler)
     app.Get("/api/stats", handlers.APIStatsHandler)
+    app.Get("/api/rescore", handlers.APIRescoreStatusHandler)
     app.Post("/skills/add", handlers.AddSkillHandler)
+    app.Post("/certifications/add", handlers.AddCertificationHandler)
+    app.Delete("/certifications/:id", handlers.DeleteCertificationHandler)
 }
 
 func startBackgroundScraping() {
@@ -7,20 +7,34 @@ import (
 )
 
 type Job struct {
-    ID          string         `gorm:"primaryKey" json:"id"`
-    Title       string         `gorm:"not null" json:"title"`
-    Company     string         `gorm:"not null" json:"company"`
-    Location    string         `json:"location"`
-    Description string         `json:"description"`
-    SalaryRange string         `json:"salary_range"`
-    Experience  string         `json:"experience"`
-    PostedDate  string         `json:"posted_date"`
-    Source      string         `json:"source"`
-    URL         string         `gorm:"unique" json:"url"`
-    Score       int            `gorm:"default:0" json:"score"`
-    Skills      datatypes.JSON `gorm:"type:json" json:"skills"`
-    TechStack   datatypes.JSON `gorm:"type:json" json:"tech_stack"`
-    CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+    ID                  string         `gorm:"primaryKey" json:"id"`
+    Title               string         `gorm:"not null" json:"title"`
+    Company             string         `gorm:"not null" json:"company"`
+    Location            string         `json:"location"`
+    Description         string         `json:"description"`
+    DescriptionHTML     string         `json:"description_html"`
+    SalaryRange         string         `json:"salary_range"`
+    Experience          string         `json:"experience"`
+    PostedDate          string         `json:"posted_date"`
+    Source              string         `json:"source"`
+    URL                 string         `gorm:"unique" json:"url"`
+    Score               int            `gorm:"default:0" json:"score"`
+    Skills              datatypes.JSON `gorm:"type:json" json:"skills"`
+    TechStack           datatypes.JSON `gorm:"type:json" json:"tech_stack"`
+    EmploymentType      string         `json:"employment_type"`
+    WorkMode            string         `json:"work_mode"`
+    ApplicationDeadline string         `json:"application_deadline"`
+    Sections            datatypes.JSON `gorm:"type:json" json:"sections"`
+    Certifications      datatypes.JSON `gorm:"type:json" json:"certifications"`
+    ScoreBreakdown      datatypes.JSON `gorm:"type:json" json:"score_breakdown"`
+    FilterAction        string         `gorm:"index;default:''" json:"filter_action"` // "", demoted or dropped
+    FilterReasons       datatypes.JSON `gorm:"type:json" json:"filter_reasons"`
+    City                string         `json:"city"`
+    Region              string         `json:"region"`
+    Country             string         `gorm:"index" json:"country"`
+    Latitude            float64        `json:"latitude"`
+    Longitude           float64        `json:"longitude"`
+    CreatedAt           time.Time      `gorm:"autoCreateTime" json:"created_at"`
 }
 
 type Application struct {
@@ -33,6 +47,12 @@ type Application struct {
     HiringManager string    `json:"hiring_manager"`
     Notes         string    `json:"notes"`
     CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+    // Interview prep pack in Markdown, written when the application moves
+    // to Interviewing
+    PrepPack    string     `gorm:"type:text" json:"prep_pack"`
+    PrepModel   string     `json:"prep_model"`
+    PrepUpdated *time.Time `json:"prep_updated"`
 }
 
 type UserSkill struct {
@@ -41,10 +61,216 @@ type UserSkill struct {
     Category string `json:"category"`
 }
 
+type UserCertification struct {
+    ID           uint      `gorm:"primaryKey" json:"id"`
+    Name         string    `gorm:"unique;not null" json:"name"`
+    CredentialID string    `json:"credential_id"`
+    IssuedDate   string    `json:"issued_date"`
+    ExpiryDate   string    `json:"expiry_date"`
+    CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type CertificationMatch struct {
+    Name       string `json:"name"`
+    Status     string `json:"status"` // met, expiring or unmet
+    ExpiryDate string `json:"expiry_date,omitempty"`
+    Detail     string `json:"detail,omitempty"`
+}
+
+// ScoringProfile holds the user's scoring preferences. Weights are the
+// maximum points of each score component; a weight of 0 disables it.
+type ScoringProfile struct {
+    ID                 uint           `gorm:"primaryKey" json:"id"`
+    SkillsWeight       int            `json:"skills_weight"`
+    ExperienceWeight   int            `json:"experience_weight"`
+    SalaryWeight       int            `json:"salary_weight"`
+    CompanyWeight      int            `json:"company_weight"`
+    LocationWeight     int            `json:"location_weight"`
+    RelevanceWeight    int            `gorm:"default:20" json:"relevance_weight"`
+    FeedbackWeight     int            `gorm:"default:15" json:"feedback_weight"`
+    SemanticWeight     int            `gorm:"default:15" json:"semantic_weight"`
+    PreferredCompanies datatypes.JSON `gorm:"type:json" json:"preferred_companies"`
+    SeniorityTarget    string         `json:"seniority_target"` // any (derive from years), junior, mid, senior or manager
+    UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// UserProfile describes the user: who they are, what they have done and what
+// they are looking for. Scoring, the analyzer and the AI generators all read
+// it. There is a single profile.
+type UserProfile struct {
+    ID              uint           `gorm:"primaryKey" json:"id"`
+    Name            string         `json:"name"`
+    Headline        string         `json:"headline"` // e.g. "SOC analyst moving into cloud security"
+    Summary         string         `gorm:"type:text" json:"summary"`
+    YearsExperience int            `json:"years_experience"`              // 0 when not set
+    WorkHistory     datatypes.JSON `gorm:"type:json" json:"work_history"` // []WorkExperience, most recent first
+    Education       datatypes.JSON `gorm:"type:json" json:"education"`    // []Education
+    Email           string         `json:"email"`
+    Phone           string         `json:"phone"`
+    Location        string         `json:"location"` // where the user lives
+    LinkedIn        string         `json:"linkedin"`
+    Website         string         `json:"website"`
+    TargetRoles     datatypes.JSON `gorm:"type:json" json:"target_roles"`
+    TargetLocations datatypes.JSON `gorm:"type:json" json:"target_locations"`
+    SalaryMin       int            `json:"salary_min"` // monthly, in KSh; 0 means no minimum
+    SalaryMax       int            `json:"salary_max"` // 0 when not set
+    UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type WorkExperience struct {
+    Title      string   `json:"title"`
+    Company    string   `json:"company"`
+    Start      string   `json:"start"` // e.g. 2021-03
+    End        string   `json:"end"`   // empty while current
+    Highlights []string `json:"highlights"`
+}
+
+type Education struct {
+    Qualification string `json:"qualification"`
+    Institution   string `json:"institution"`
+    Year          string `json:"year"`
+}
+
+// Resume is an uploaded resume file with the text read from it. Proposal
+// holds what the resume suggests adding to the profile until the user
+// reviews it.
+type Resume struct {
+    ID          uint           `gorm:"primaryKey" json:"id"`
+    Filename    string         `json:"filename"`
+    ContentType string         `json:"content_type"`
+    Size        int            `json:"size"`
+    Data        []byte         `json:"-"`
+    Text        string         `gorm:"type:text" json:"text"`
+    Proposal    datatypes.JSON `gorm:"type:json" json:"proposal"` // ResumeProposal
+    ReviewedAt  *time.Time     `json:"reviewed_at"`
+    CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ResumeProposal lists what a resume has that the profile does not
+type ResumeProposal struct {
+    Skills          []string         `json:"skills"`
+    Certifications  []string         `json:"certifications"`
+    YearsExperience int              `json:"years_experience"` // 0 when nothing new
+    WorkHistory     []WorkExperience `json:"work_history"`
+}
+
+// ExclusionRule is a user-defined deal-breaker. Kind is keyword, company,
+// title (a regular expression) or min_salary; Action is drop or demote.
+type ExclusionRule struct {
+    ID        uint      `gorm:"primaryKey" json:"id"`
+    Kind      string    `gorm:"not null" json:"kind"`
+    Value     string    `gorm:"not null" json:"value"`
+    Action    string    `gorm:"not null" json:"action"`
+    CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// JobFeedback is the user's verdict on a job, liked or dismissed, used to
+// train the feedback model
+type JobFeedback struct {
+    ID        uint      `gorm:"primaryKey" json:"id"`
+    JobID     string    `gorm:"uniqueIndex;not null" json:"job_id"`
+    Signal    string    `gorm:"not null" json:"signal"`
+    UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Embedding is a cached vector for a text, keyed by a hash of the model and
+// text so it is only computed once
+type Embedding struct {
+    Hash      string    `gorm:"primaryKey" json:"hash"`
+    Model     string    `gorm:"index;not null" json:"model"`
+    Vector    []byte    `gorm:"not null" json:"-"` // little-endian float32s
+    CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CoverLetter is a cover letter for a job, keeping every version that was
+// generated or edited. Content and Model mirror the latest version. JobID is
+// empty for letters written from a pasted description on the analyzer page.
+type CoverLetter struct {
+    ID            uint                 `gorm:"primaryKey" json:"id"`
+    JobID         string               `gorm:"index" json:"job_id"`
+    ApplicationID string               `gorm:"index" json:"application_id"`
+    JobTitle      string               `json:"job_title"`
+    Company       string               `json:"company"`
+    Content       string               `gorm:"type:text;not null" json:"content"`
+    Model         string               `json:"model"` // provider/model, "template" for the fallback, or "edited"
+    Versions      []CoverLetterVersion `gorm:"constraint:OnDelete:CASCADE" json:"versions,omitempty"`
+    CreatedAt     time.Time            `gorm:"autoCreateTime" json:"created_at"`
+    UpdatedAt     time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CoverLetterVersion is one generated or hand-edited revision of a letter
+type CoverLetterVersion struct {
+    ID            uint           `gorm:"primaryKey" json:"id"`
+    CoverLetterID uint           `gorm:"index;not null" json:"cover_letter_id"`
+    Version       int            `gorm:"not null" json:"version"`
+    Source        string         `gorm:"not null" json:"source"` // generated or edited
+    Content       string         `gorm:"type:text;not null" json:"content"`
+    Model         string         `json:"model"`
+    Inputs        datatypes.JSON `gorm:"type:json" json:"inputs"` // what the prompt was built from; empty for edits
+    CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TailoredResume is a resume rewritten for one job from the user's profile,
+// in Markdown. Every generated variant is kept; the latest is attached to
+// the application when the user applies.
+type TailoredResume struct {
+    ID             uint           `gorm:"primaryKey" json:"id"`
+    JobID          string         `gorm:"index;not null" json:"job_id"`
+    ApplicationID  string         `gorm:"index" json:"application_id"`
+    JobTitle       string         `json:"job_title"`
+    Company        string         `json:"company"`
+    Content        string         `gorm:"type:text;not null" json:"content"`
+    Model          string         `json:"model"`                            // provider/model, or "template" for the fallback
+    MatchingSkills datatypes.JSON `gorm:"type:json" json:"matching_skills"` // the skills it puts first
+    CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// PromptOverride is the user's own version of a prompt template, replacing
+// the file or built-in default of the same name
+type PromptOverride struct {
+    Name      string    `gorm:"primaryKey" json:"name"`
+    Body      string    `gorm:"type:text;not null" json:"body"`
+    UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ScoreBreakdown explains how a job's score was put together
+type ScoreBreakdown struct {
+    Total           int              `json:"total"`
+    Components      []ScoreComponent `json:"components"`
+    MatchedSkills   []SkillMatch     `json:"matched_skills"`
+    UnmatchedSkills []string         `json:"unmatched_skills"`
+}
+
+type ScoreComponent struct {
+    Name      string `json:"name"`
+    Points    int    `json:"points"`
+    MaxPoints int    `json:"max_points"`
+    Rule      string `json:"rule"` // the rule that fired, e.g. mentions "senior"
+}
+
+type SkillMatch struct {
+    Skill  string  `json:"skill"`
+    Weight float64 `json:"weight"` // 1 for a title or requirements match, less elsewhere
+    Where  string  `json:"where"`  // "title" or the section kind it was found in
+}
+
 type SkillsAnalysis struct {
-    MissingSkills   []string `json:"missing_skills"`
-    MatchingSkills  []string `json:"matching_skills"`
-    Transferable    []string `json:"transferable_skills"`
-    FitScore        int      `json:"fit_score"`
-    Recommendations []string `json:"recommendations"`
-}
\ No newline at end of file
+    MissingSkills    []string             `json:"missing_skills"`
+    MatchingSkills   []string             `json:"matching_skills"`
+    Transferable     []string             `json:"transferable_skills"`
+    FitScore         int                  `json:"fit_score"`
+    Recommendations  []string             `json:"recommendations"`
+    NiceToHaveSkills []string             `json:"nice_to_have_skills"`
+    Certifications   []CertificationMatch `json:"certifications"`
+}
+
+// CompanyNote is what the user keeps about a company: their own notes and
+// the last briefing summary the AI provider wrote for it
+type CompanyNote struct {
+    Company      string     `gorm:"primaryKey" json:"company"` // lowercased and trimmed
+    Notes        string     `gorm:"type:text" json:"notes"`
+    Summary      string     `gorm:"type:text" json:"summary"`
+    SummaryModel string     `json:"summary_model"`
+    SummarizedAt *time.Time `json:"summarized_at"`
+    UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,141 @@
+// Package profile turns the stored user profile into the lists and text the
+// rest of the app works with.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+func WorkHistory(p *models.UserProfile) []models.WorkExperience {
+	var history []models.WorkExperience
+	if len(p.WorkHistory) > 0 {
+		json.Unmarshal(p.WorkHistory, &history)
+	}
+	return history
+}
+
+func Education(p *models.UserProfile) []models.Education {
+	var education []models.Education
+	if len(p.Education) > 0 {
+		json.Unmarshal(p.Education, &education)
+	}
+	return education
+}
+
+func TargetRoles(p *models.UserProfile) []string {
+	return stringList(p.TargetRoles)
+}
+
+func TargetLocations(p *models.UserProfile) []string {
+	return stringList(p.TargetLocations)
+}
+
+// Text is everything the user has written about their work, for ranking
+// jobs against: headline, summary, target roles, positions with their
+// highlights, and education. It is empty for a blank profile.
+func Text(p *models.UserProfile) string {
+	var parts []string
+	add := func(text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	add(p.Headline)
+	add(p.Summary)
+	add(strings.Join(TargetRoles(p), ", "))
+	for _, job := range WorkHistory(p) {
+		add(strings.TrimSpace(job.Title + " " + job.Company))
+		for _, highlight := range job.Highlights {
+			add(highlight)
+		}
+	}
+	for _, education := range Education(p) {
+		add(strings.TrimSpace(education.Qualification + " " + education.Institution))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// RankingText is what jobs are ranked against: the profile Text, the
+// user's skills and the text of their latest resume. It is empty when the
+// user has given none of them.
+func RankingText(p *models.UserProfile, skills []string, resumeText string) string {
+	var parts []string
+	for _, part := range []string{Text(p), strings.Join(skills, ", "), strings.TrimSpace(resumeText)} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Bio describes the user for a prompt: headline, experience, skills,
+// summary and recent positions
+func Bio(p *models.UserProfile, skills []string) string {
+	var lines []string
+	if p.Headline != "" {
+		lines = append(lines, p.Headline)
+	}
+	if p.YearsExperience > 0 {
+		lines = append(lines, fmt.Sprintf("%d years of experience.", p.YearsExperience))
+	}
+	if len(skills) > 0 {
+		lines = append(lines, "Skills: "+strings.Join(skills, ", ")+".")
+	}
+	if summary := strings.TrimSpace(p.Summary); summary != "" {
+		lines = append(lines, summary)
+	}
+
+	history := WorkHistory(p)
+	if len(history) > 0 {
+		lines = append(lines, "Experience:")
+	}
+	for _, job := range history {
+		period := job.Start
+		if period != "" {
+			end := job.End
+			if end == "" {
+				end = "present"
+			}
+			period = " (" + period + " to " + end + ")"
+		}
+		line := "- " + job.Title
+		if job.Company != "" {
+			line += " at " + job.Company
+		}
+		line += period
+		for _, highlight := range job.Highlights {
+			line += "\n  - " + highlight
+		}
+		lines = append(lines, line)
+	}
+
+	educations := Education(p)
+	if len(educations) > 0 {
+		lines = append(lines, "Education:")
+	}
+	for _, education := range educations {
+		line := "- " + education.Qualification
+		if education.Institution != "" {
+			line += ", " + education.Institution
+		}
+		if education.Year != "" {
+			line += " (" + education.Year + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func stringList(data []byte) []string {
+	var values []string
+	if len(data) > 0 {
+		json.Unmarshal(data, &values)
+	}
+	return values
+}
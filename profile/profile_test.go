@@ -0,0 +1,29 @@
+package profile
+
+import (
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+func TestRankingText(t *testing.T) {
+	user := &models.UserProfile{Headline: "SOC Analyst", TargetRoles: []byte(`["Security Engineer"]`)}
+
+	tests := []struct {
+		name   string
+		user   *models.UserProfile
+		skills []string
+		resume string
+		want   string
+	}{
+		{"blank", &models.UserProfile{}, nil, "  ", ""},
+		{"profile only", user, nil, "", "SOC Analyst\nSecurity Engineer"},
+		{"skills and resume", &models.UserProfile{}, []string{"Splunk", "Linux"}, "Ran incident response at Acme.\n", "Splunk, Linux\nRan incident response at Acme."},
+		{"everything", user, []string{"Splunk"}, "Acme Bank", "SOC Analyst\nSecurity Engineer\nSplunk\nAcme Bank"},
+	}
+	for _, tt := range tests {
+		if got := RankingText(tt.user, tt.skills, tt.resume); got != tt.want {
+			t.Errorf("%s: RankingText = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
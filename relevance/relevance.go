@@ -0,0 +1,263 @@
+package relevance
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// BM25 parameters: k1 controls how quickly repeated terms stop adding to the
+// score, b how much long documents are penalized
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Words too common in job postings and profiles to say anything about fit
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`a about above after all also am an and any are as at be been being
+		both but by can could did do does doing during each etc for from further had has have having he her
+		here hers him his how i if in into is it its itself just me more most my no nor not of off on once
+		only or other our ours out over own same she should so some such than that the their theirs them
+		then there these they this those through to too under until up very was we were what when where
+		which while who whom why will with would you your yours yourself
+		ability able applicant apply candidate company etc including job looking must new opportunity
+		per plus position preferred required requirement responsibilities responsible role strong team
+		understanding using well work working year years`) {
+		stopwords[word] = true
+	}
+}
+
+// Tokenize splits text into lowercase terms, dropping stopwords and folding
+// simple plurals so "firewalls" matches "firewall"
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.Trim(field, "+#")
+		if len(field) < 2 || stopwords[field] {
+			continue
+		}
+		terms = append(terms, stem(field))
+	}
+	return terms
+}
+
+func stem(term string) string {
+	switch {
+	case len(term) > 4 && strings.HasSuffix(term, "ies"):
+		return term[:len(term)-3] + "y"
+	case len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") && !strings.HasSuffix(term, "us") && !strings.HasSuffix(term, "is"):
+		return term[:len(term)-1]
+	}
+	return term
+}
+
+// Query is the term counts of the text documents are ranked against
+type Query map[string]int
+
+// NewQuery tokenizes text into a query
+func NewQuery(text string) Query {
+	query := Query{}
+	for _, term := range Tokenize(text) {
+		query[term]++
+	}
+	return query
+}
+
+// Match is how one document scored against a query
+type Match struct {
+	Score float64
+	Terms []string // query terms found, most significant first
+}
+
+// Index keeps the term statistics of a corpus of documents for BM25 ranking.
+// It is safe for concurrent use.
+type Index struct {
+	mu          sync.RWMutex
+	docs        map[string]map[string]int // term frequencies by document ID
+	lengths     map[string]int
+	docFreq     map[string]int // number of documents containing each term
+	totalLength int
+
+	maxScores map[string]*maxScore // by query key
+}
+
+// maxScoreDrift is how much the index may grow before a cached best score is
+// computed again in full. In between, each new document can raise it, but the
+// small shift in the other documents' scores as the statistics change is
+// ignored.
+const maxScoreDrift = 0.1
+
+// maxScore is the best score any document got for a query
+type maxScore struct {
+	query Query
+	score float64
+	docs  int // documents indexed when it was last computed in full
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:      map[string]map[string]int{},
+		lengths:   map[string]int{},
+		docFreq:   map[string]int{},
+		maxScores: map[string]*maxScore{},
+	}
+}
+
+// Add indexes text under id, replacing any earlier text for that id
+func (idx *Index) Add(id, text string) {
+	terms := Tokenize(text)
+	freqs := make(map[string]int, len(terms))
+	for _, term := range terms {
+		freqs[term]++
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	replaced := idx.remove(id)
+	idx.docs[id] = freqs
+	idx.lengths[id] = len(terms)
+	idx.totalLength += len(terms)
+	for term := range freqs {
+		idx.docFreq[term]++
+	}
+
+	// The replaced text may have been the best match
+	if replaced {
+		clear(idx.maxScores)
+	}
+	for _, cached := range idx.maxScores {
+		cached.score = max(cached.score, idx.score(cached.query, id).Score)
+	}
+}
+
+func (idx *Index) remove(id string) bool {
+	freqs, ok := idx.docs[id]
+	if !ok {
+		return false
+	}
+	for term := range freqs {
+		if idx.docFreq[term]--; idx.docFreq[term] == 0 {
+			delete(idx.docFreq, term)
+		}
+	}
+	idx.totalLength -= idx.lengths[id]
+	delete(idx.docs, id)
+	delete(idx.lengths, id)
+	return true
+}
+
+// Contains reports whether a document is indexed under id
+func (idx *Index) Contains(id string) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	_, ok := idx.docs[id]
+	return ok
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Score ranks the document indexed under id against the query with BM25
+func (idx *Index) Score(query Query, id string) Match {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.score(query, id)
+}
+
+func (idx *Index) score(query Query, id string) Match {
+	var match Match
+	freqs, ok := idx.docs[id]
+	if !ok || len(idx.docs) == 0 {
+		return match
+	}
+
+	avgLength := float64(idx.totalLength) / float64(len(idx.docs))
+	lengthNorm := 1 - b
+	if avgLength > 0 {
+		lengthNorm += b * float64(idx.lengths[id]) / avgLength
+	}
+
+	// Summed in a fixed order so the same document always gets the same score
+	contributions := map[string]float64{}
+	for _, term := range query.terms() {
+		tf := float64(freqs[term])
+		if tf == 0 {
+			continue
+		}
+		contribution := idx.idf(term) * tf * (k1 + 1) / (tf + k1*lengthNorm) * float64(query[term])
+		contributions[term] = contribution
+		match.Score += contribution
+		match.Terms = append(match.Terms, term)
+	}
+
+	sort.SliceStable(match.Terms, func(i, j int) bool {
+		return contributions[match.Terms[i]] > contributions[match.Terms[j]]
+	})
+	return match
+}
+
+// idf is the BM25 inverse document frequency, kept positive for terms found
+// in most documents
+func (idx *Index) idf(term string) float64 {
+	n, df := float64(len(idx.docs)), float64(idx.docFreq[term])
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// MaxScore returns the best score any indexed document gets for the query,
+// used to put scores on a 0-1 scale. It is cached and kept up to date as
+// documents are added, and computed again in full once the index has grown
+// by maxScoreDrift, so scoring each new job doesn't rescan the corpus.
+func (idx *Index) MaxScore(query Query) float64 {
+	key := query.key()
+
+	idx.mu.RLock()
+	cached, ok := idx.maxScores[key]
+	if ok && float64(len(idx.docs)) <= float64(cached.docs)*(1+maxScoreDrift) {
+		best := cached.score
+		idx.mu.RUnlock()
+		return best
+	}
+	idx.mu.RUnlock()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	best := 0.0
+	for id := range idx.docs {
+		best = max(best, idx.score(query, id).Score)
+	}
+	idx.maxScores[key] = &maxScore{query: query, score: best, docs: len(idx.docs)}
+	return best
+}
+
+func (q Query) terms() []string {
+	terms := make([]string, 0, len(q))
+	for term := range q {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func (q Query) key() string {
+	var key strings.Builder
+	for _, term := range q.terms() {
+		key.WriteString(term + ":" + strconv.Itoa(q[term]) + " ")
+	}
+	return key.String()
+}
@@ -0,0 +1,86 @@
+package relevance
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Managing Firewalls, IDS/IPS and C++ policies for the SOC team")
+	want := []string{"managing", "firewall", "ids", "ips", "policy", "soc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestScoreRanksMatchingDocumentsHigher(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("soc", "SOC analyst triaging Splunk alerts and tuning SIEM rules")
+	idx.Add("dev", "Frontend developer building React dashboards")
+	idx.Add("net", "Network engineer running Fortinet firewalls")
+
+	query := NewQuery("Splunk SIEM analyst")
+	soc, dev := idx.Score(query, "soc"), idx.Score(query, "dev")
+	if soc.Score <= 0 || dev.Score != 0 {
+		t.Errorf("scores soc %v, dev %v", soc.Score, dev.Score)
+	}
+	if len(soc.Terms) != 3 {
+		t.Errorf("matched terms = %q, want all three", soc.Terms)
+	}
+	if missing := idx.Score(query, "missing"); missing.Score != 0 {
+		t.Errorf("unknown document scored %v", missing.Score)
+	}
+}
+
+// bruteMaxScore is MaxScore without the cache
+func bruteMaxScore(idx *Index, query Query) float64 {
+	best := 0.0
+	for id := range idx.docs {
+		best = max(best, idx.score(query, id).Score)
+	}
+	return best
+}
+
+func TestMaxScoreTracksAdds(t *testing.T) {
+	idx := NewIndex()
+	query := NewQuery("splunk siem incident response")
+	for i := 0; i < 50; i++ {
+		idx.Add(fmt.Sprintf("filler-%d", i), fmt.Sprintf("accountant role number %d with excel", i))
+	}
+	idx.Add("weak", "some splunk exposure")
+	if got, want := idx.MaxScore(query), bruteMaxScore(idx, query); got != want {
+		t.Fatalf("MaxScore = %v, want %v", got, want)
+	}
+
+	// A better match added afterwards raises the cached best straight away
+	idx.Add("strong", "splunk siem incident response splunk siem")
+	got, want := idx.MaxScore(query), bruteMaxScore(idx, query)
+	if math.Abs(got-want) > want*0.05 {
+		t.Errorf("after adding a better match MaxScore = %v, want about %v", got, want)
+	}
+	if got < idx.Score(query, "strong").Score {
+		t.Errorf("MaxScore %v is below the new document's score", got)
+	}
+
+	// Replacing the best document drops it from the best score
+	idx.Add("strong", "accountant")
+	if got, want := idx.MaxScore(query), bruteMaxScore(idx, query); got != want {
+		t.Errorf("after replacing the best match MaxScore = %v, want %v", got, want)
+	}
+}
+
+func TestMaxScoreRecomputedAsIndexGrows(t *testing.T) {
+	idx := NewIndex()
+	query := NewQuery("kubernetes")
+	idx.Add("first", "kubernetes")
+	idx.MaxScore(query)
+
+	for i := 0; i < 100; i++ {
+		idx.Add(fmt.Sprintf("doc-%d", i), "kubernetes operator with helm and terraform")
+	}
+	if got, want := idx.MaxScore(query), bruteMaxScore(idx, query); got != want {
+		t.Errorf("MaxScore = %v, want it recomputed as %v", got, want)
+	}
+}
@@ -0,0 +1,198 @@
+package resume
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// maxHighlights is how many bullet points are kept per position
+const maxHighlights = 6
+
+const datePattern = `(?:(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?,?\s+\d{4}|\d{1,2}/\d{4}|\d{4})`
+
+var (
+	// dateRangePattern matches "Mar 2021 - Present", "01/2019 – 06/2020" or
+	// "2018 to 2020"
+	dateRangePattern = regexp.MustCompile(`(?i)\b(` + datePattern + `)\s*(?:-|–|—|to|until)\s*(` + datePattern + `|present|current|now|today|date)\b`)
+
+	statedYearsPattern = regexp.MustCompile(`(?i)(\d{1,2})\+?\s*years?\s+(?:of\s+)?(?:professional\s+|work\s+|industry\s+|hands-on\s+|relevant\s+)?experience`)
+
+	// educationPattern marks date lines that are studies rather than jobs
+	educationPattern = regexp.MustCompile(`(?i)\b(bsc|b\.sc|msc|m\.sc|mba|phd|bachelor|master|diploma|degree|university|college|school|academy|kcse|kcpe)\b`)
+
+	// headingSeparators split "Title at Company", "Title | Company" and the like
+	headingSeparators = []string{" at ", " @ ", " | ", " — ", " – ", " - ", ", "}
+
+	sectionHeadingPattern = regexp.MustCompile(`(?i)^\s*((work|professional|employment|career)\s+)?(experience|history|employment)(\s+history)?:?\s*$`)
+
+	bulletPrefix = regexp.MustCompile(`^\s*[•●▪◦‣∙·*–-]\s*`)
+
+	months = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+)
+
+// Positions finds the jobs a resume lists. A position is a line with a date
+// range; its title and company come from the rest of that line or the line
+// above, and the bullet points below it become highlights. Studies are
+// skipped. Positions are returned in the order they appear.
+func Positions(text string) []models.WorkExperience {
+	lines := strings.Split(text, "\n")
+	var positions []models.WorkExperience
+
+	for i, line := range lines {
+		match := dateRangePattern.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+		start := parseDate(line[match[2]:match[3]], false)
+		end := parseDate(line[match[4]:match[5]], true)
+		if start == "" || (end != "" && end < start) {
+			continue
+		}
+
+		heading := strings.TrimSpace(line[:match[0]] + " " + line[match[1]:])
+		heading = strings.Trim(heading, " \t|,()–—-")
+		previous := ""
+		if i > 0 && !dateRangePattern.MatchString(lines[i-1]) && !bulletPrefix.MatchString(lines[i-1]) && !sectionHeadingPattern.MatchString(lines[i-1]) {
+			previous = strings.TrimSpace(lines[i-1])
+		}
+		if educationPattern.MatchString(heading) || (heading == "" && educationPattern.MatchString(previous)) {
+			continue
+		}
+
+		title, company := splitHeading(heading)
+		switch {
+		case title == "":
+			title, company = splitHeading(previous)
+		case company == "" && len(previous) <= 60:
+			// "Safaricom PLC" on one line, "SOC Analyst   2021 - Present" on the next
+			company = previous
+		}
+		if title == "" {
+			continue
+		}
+
+		position := models.WorkExperience{Title: title, Company: company, Start: start, End: end}
+		skipped := 0
+		for _, next := range lines[i+1:] {
+			if dateRangePattern.MatchString(next) || len(position.Highlights) >= maxHighlights {
+				break
+			}
+			if !bulletPrefix.MatchString(next) {
+				// Allow a line or two, such as a location, before the bullets
+				if skipped++; len(position.Highlights) > 0 || skipped > 2 {
+					break
+				}
+				continue
+			}
+			if highlight := strings.TrimSpace(bulletPrefix.ReplaceAllString(next, "")); highlight != "" {
+				position.Highlights = append(position.Highlights, highlight)
+			}
+		}
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// YearsOfExperience is the largest "N years of experience" the resume states,
+// or else the time covered by its positions, counting overlaps once
+func YearsOfExperience(text string, positions []models.WorkExperience, now time.Time) int {
+	stated := 0
+	for _, match := range statedYearsPattern.FindAllStringSubmatch(text, -1) {
+		if years, err := strconv.Atoi(match[1]); err == nil && years > stated && years <= 60 {
+			stated = years
+		}
+	}
+	if stated > 0 {
+		return stated
+	}
+
+	type span struct{ start, end int } // in months since year 0
+	var spans []span
+	for _, position := range positions {
+		start, ok := monthIndex(position.Start)
+		if !ok {
+			continue
+		}
+		end, ok := monthIndex(position.End)
+		if !ok {
+			end = now.Year()*12 + int(now.Month()) - 1
+		}
+		if end >= start {
+			spans = append(spans, span{start, end + 1})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	total, reached := 0, 0
+	for _, s := range spans {
+		if s.start < reached {
+			s.start = reached
+		}
+		if s.end > s.start {
+			total += s.end - s.start
+			reached = s.end
+		}
+	}
+	return total / 12
+}
+
+func splitHeading(heading string) (string, string) {
+	heading = strings.TrimSpace(heading)
+	for _, separator := range headingSeparators {
+		if parts := strings.SplitN(heading, separator, 2); len(parts) == 2 {
+			return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		}
+	}
+	return heading, ""
+}
+
+// parseDate turns a resume date into the YYYY-MM form the profile uses. A
+// bare year is taken as January for a start and December for an end.
+// "Present" and the like give "" for an ongoing position.
+func parseDate(value string, isEnd bool) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "present", "current", "now", "today", "date":
+		return ""
+	}
+
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == '/' || r == '.' || r == ',' })
+	year, month := 0, 1
+	if isEnd {
+		month = 12
+	}
+	switch len(fields) {
+	case 1:
+		year, _ = strconv.Atoi(fields[0])
+	case 2:
+		year, _ = strconv.Atoi(fields[1])
+		if m, ok := months[fields[0][:min(3, len(fields[0]))]]; ok {
+			month = m
+		} else if m, err := strconv.Atoi(fields[0]); err == nil && m >= 1 && m <= 12 {
+			month = m
+		} else {
+			return ""
+		}
+	}
+	if year < 1950 || year > 2100 {
+		return ""
+	}
+	return fmt.Sprintf("%d-%02d", year, month)
+}
+
+func monthIndex(date string) (int, bool) {
+	t, err := time.Parse("2006-01", date)
+	if err != nil {
+		return 0, false
+	}
+	return t.Year()*12 + int(t.Month()) - 1, true
+}
@@ -0,0 +1,115 @@
+package resume
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+const testResume = `Jane Doe
+jane@example.com
+
+Work Experience
+Safaricom PLC
+SOC Analyst   Mar 2021 - Present
+Nairobi
+• Triaged alerts in Splunk
+• Wrote detection rules for phishing
+Network Engineer at Acme Ltd | 01/2019 – 02/2021
+- Ran the Fortinet firewalls
+- Moved the branch offices to SD-WAN
+Intern, KenGen (2017 to 2018)
+
+Education
+BSc Computer Science, University of Nairobi 2013 - 2017
+`
+
+func TestPositions(t *testing.T) {
+	want := []models.WorkExperience{
+		{
+			Title:      "SOC Analyst",
+			Company:    "Safaricom PLC",
+			Start:      "2021-03",
+			Highlights: []string{"Triaged alerts in Splunk", "Wrote detection rules for phishing"},
+		},
+		{
+			Title:      "Network Engineer",
+			Company:    "Acme Ltd",
+			Start:      "2019-01",
+			End:        "2021-02",
+			Highlights: []string{"Ran the Fortinet firewalls", "Moved the branch offices to SD-WAN"},
+		},
+		{Title: "Intern", Company: "KenGen", Start: "2017-01", End: "2018-12"},
+	}
+
+	got := Positions(testResume)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Positions =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestPositionsSkipsInvalidRanges(t *testing.T) {
+	for _, text := range []string{
+		"Engineer at Acme 2020 - 2018", // ends before it starts
+		"Engineer at Acme 1900 - 1920", // out of range
+		"Diploma in IT, Kenya Polytechnic 2010 - 2012",
+		"2015 - 2016", // no title anywhere
+	} {
+		if got := Positions(text); len(got) != 0 {
+			t.Errorf("Positions(%q) = %+v, want none", text, got)
+		}
+	}
+}
+
+func TestYearsOfExperience(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		text      string
+		positions []models.WorkExperience
+		want      int
+	}{
+		{
+			name: "largest stated figure",
+			text: "Analyst with 5 years of experience, including 7+ years of hands-on experience with Linux",
+			want: 7,
+		},
+		{
+			name:      "implausible statement ignored",
+			text:      "99 years of experience",
+			positions: []models.WorkExperience{{Start: "2020-01", End: "2021-12"}},
+			want:      2,
+		},
+		{
+			name: "overlaps counted once",
+			positions: []models.WorkExperience{
+				{Start: "2019-01", End: "2021-02"},
+				{Start: "2020-06", End: "2022-12"},
+			},
+			want: 4,
+		},
+		{
+			name: "ongoing position runs to now",
+			positions: []models.WorkExperience{
+				{Start: "2021-03"},
+				{Start: "2018-01", End: "2018-12"},
+			},
+			want: 6,
+		},
+		{
+			name:      "unparseable dates skipped",
+			positions: []models.WorkExperience{{Start: "sometime"}, {Start: "2023-03", End: "2024-02"}},
+			want:      1,
+		},
+		{name: "nothing to go on", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := YearsOfExperience(tt.text, tt.positions, now); got != tt.want {
+				t.Errorf("YearsOfExperience = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
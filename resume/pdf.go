@@ -0,0 +1,605 @@
+package resume
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxStreamSize caps how much of one PDF stream is decompressed
+const maxStreamSize = 10 << 20
+
+// maxCMapRange caps how many codes one ToUnicode bfrange line may map, and
+// maxCMapCodes how many a whole CMap may map
+const (
+	maxCMapRange = 1 << 16
+	maxCMapCodes = 1 << 20
+)
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfToUnicodeRef = regexp.MustCompile(`/ToUnicode\s+(\d+)\s+\d+\s+R`)
+	pdfNamedRef     = regexp.MustCompile(`/([^\s()<>\[\]{}/%]+)\s+(\d+)\s+\d+\s+R`)
+)
+
+// pdfText pulls the text out of a PDF's content streams. It handles the
+// uncompressed and Flate-compressed streams that word processors and resume
+// builders write, reading strings as Latin-1 or UTF-16. Fonts with a
+// ToUnicode character map, such as the Identity-H fonts that Chrome, Google
+// Docs and Canva embed, are read through that map. Fonts stored in
+// compressed object streams are not found, so their glyph IDs come out empty.
+func pdfText(data []byte) (string, error) {
+	streams := pdfStreams(data)
+	fonts := pdfFonts(data, streams)
+
+	var text strings.Builder
+	for _, stream := range streams {
+		if !bytes.Contains(stream.content, []byte("BT")) {
+			continue
+		}
+		text.WriteString(contentText(stream.content, fonts))
+		text.WriteString("\n")
+	}
+	return text.String(), nil
+}
+
+// pdfStream is a decoded stream that may hold page content or a character map
+type pdfStream struct {
+	object  int // object number, or -1 when the header cannot be read
+	content []byte
+}
+
+// pdfStreams finds the streams in a PDF and decodes those that may hold page
+// content or a ToUnicode character map
+func pdfStreams(data []byte) []pdfStream {
+	var streams []pdfStream
+
+	for offset := 0; ; {
+		start := bytes.Index(data[offset:], []byte("stream"))
+		if start < 0 {
+			break
+		}
+		start += offset
+		offset = start + len("stream")
+
+		// Skip "endstream" and anything that is not a stream keyword
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			continue
+		}
+		body := offset
+		if body < len(data) && data[body] == '\r' {
+			body++
+		}
+		if body < len(data) && data[body] == '\n' {
+			body++
+		}
+		if body == offset {
+			continue
+		}
+
+		end := bytes.Index(data[body:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		end += body
+		offset = end + len("endstream")
+
+		header := data[:start]
+		object := -1
+		if obj := bytes.LastIndex(header, []byte("obj")); obj >= 0 {
+			object = objectNumber(data[max(0, obj-32):obj])
+			header = header[obj:]
+		}
+		content, ok := streamContent(header, data[body:end])
+		if !ok || !bytes.Contains(content, []byte("BT")) && !bytes.Contains(content, []byte("begincmap")) {
+			continue
+		}
+		streams = append(streams, pdfStream{object: object, content: content})
+	}
+
+	return streams
+}
+
+// objectNumber reads the object number from the "12 0" before an obj keyword
+func objectNumber(before []byte) int {
+	fields := bytes.Fields(before)
+	if len(fields) < 2 {
+		return -1
+	}
+	number, err := strconv.Atoi(string(fields[len(fields)-2]))
+	if err != nil {
+		return -1
+	}
+	return number
+}
+
+// pdfFonts maps font resource names, such as F1, to the ToUnicode character
+// maps of the fonts they name
+func pdfFonts(data []byte, streams []pdfStream) map[string]*toUnicode {
+	cmaps := make(map[int]*toUnicode)
+	for _, stream := range streams {
+		if stream.object < 0 || !bytes.Contains(stream.content, []byte("begincmap")) {
+			continue
+		}
+		if cmap := parseCMap(stream.content); cmap != nil {
+			cmaps[stream.object] = cmap
+		}
+	}
+	if len(cmaps) == 0 {
+		return nil
+	}
+
+	// Font dictionaries point at their character map with /ToUnicode 12 0 R
+	fontCMaps := make(map[string]*toUnicode)
+	headers := pdfObjectHeader.FindAllSubmatchIndex(data, -1)
+	for i, header := range headers {
+		end := len(data)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		dict := data[header[1]:end]
+		if stream := bytes.Index(dict, []byte("stream")); stream >= 0 {
+			dict = dict[:stream]
+		}
+		ref := pdfToUnicodeRef.FindSubmatch(dict)
+		if ref == nil {
+			continue
+		}
+		object, _ := strconv.Atoi(string(ref[1]))
+		if cmap, ok := cmaps[object]; ok {
+			fontCMaps[string(data[header[2]:header[3]])] = cmap
+		}
+	}
+
+	// Resource dictionaries name the fonts with /F1 7 0 R
+	fonts := make(map[string]*toUnicode)
+	for _, ref := range pdfNamedRef.FindAllSubmatch(data, -1) {
+		if cmap, ok := fontCMaps[string(ref[2])]; ok {
+			fonts[string(ref[1])] = cmap
+		}
+	}
+	return fonts
+}
+
+// streamContent decodes a stream that may hold page content, skipping images,
+// fonts and streams in filters other than Flate
+func streamContent(header, raw []byte) ([]byte, bool) {
+	for _, skip := range []string{"/Image", "/FontFile", "/Length1", "/XRef", "/ObjStm", "/DCTDecode", "/JPXDecode", "/CCITTFaxDecode"} {
+		if bytes.Contains(header, []byte(skip)) {
+			return nil, false
+		}
+	}
+	if !bytes.Contains(header, []byte("/FlateDecode")) {
+		if bytes.Contains(header, []byte("/Filter")) {
+			return nil, false
+		}
+		return raw, true
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, false
+	}
+	defer reader.Close()
+	// A truncated stream still yields the text before the damage
+	content, _ := io.ReadAll(io.LimitReader(reader, maxStreamSize))
+	return content, len(content) > 0
+}
+
+// contentText runs the text operators of a content stream: strings shown
+// with Tj, TJ, ' and " are written out, and moves to a new line break the
+// text into lines. Strings in a font selected with Tf are read through its
+// character map in fonts.
+func contentText(content []byte, fonts map[string]*toUnicode) string {
+	var text strings.Builder
+	var operands []string // strings waiting for their operator
+	var numbers []float64 // numbers waiting for their operator
+	var array []string    // pieces of a TJ array
+	var name string       // name waiting for its operator
+	var font *toUnicode
+	inArray := false
+	lastY := -1.0
+	absolute := false
+
+	decode := func(raw []byte) string {
+		if font != nil {
+			return font.decode(raw)
+		}
+		return decodePDFString(raw)
+	}
+
+	newline := func() {
+		if text.Len() > 0 && !strings.HasSuffix(text.String(), "\n") {
+			text.WriteString("\n")
+		}
+	}
+	space := func() {
+		if text.Len() > 0 && !strings.HasSuffix(text.String(), " ") && !strings.HasSuffix(text.String(), "\n") {
+			text.WriteString(" ")
+		}
+	}
+	// Text placed at a new height starts a new line
+	moveTo := func(y float64) {
+		if y != lastY {
+			newline()
+			lastY = y
+		} else {
+			space()
+		}
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			raw, next := literalString(content, i)
+			s := decode(raw)
+			i = next
+			if inArray {
+				array = append(array, s)
+			} else {
+				operands = append(operands, s)
+			}
+		case c == '<' && i+1 < len(content) && content[i+1] != '<':
+			raw, next := hexString(content, i)
+			s := decode(raw)
+			i = next
+			if inArray {
+				array = append(array, s)
+			} else {
+				operands = append(operands, s)
+			}
+		case c == '[':
+			inArray, array = true, nil
+			i++
+		case c == ']':
+			inArray = false
+			operands = append(operands, strings.Join(array, ""))
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case isPDFSpace(c) || c == '<' || c == '>' || c == ')' || c == '{' || c == '}' || c == '/':
+			i++
+			if c == '/' {
+				start := i
+				for i < len(content) && !isPDFSpace(content[i]) && !isPDFDelimiter(content[i]) {
+					i++
+				}
+				name = string(content[start:i])
+			}
+		default:
+			start := i
+			for i < len(content) && !isPDFSpace(content[i]) && !isPDFDelimiter(content[i]) {
+				i++
+			}
+			if i == start {
+				// A delimiter with no meaning here, such as a stray ")"
+				i++
+				continue
+			}
+			word := string(content[start:i])
+
+			if number, err := strconv.ParseFloat(word, 64); err == nil {
+				if inArray {
+					// A large gap between pieces of a TJ array is a space
+					if number < -200 {
+						array = append(array, " ")
+					}
+				} else {
+					numbers = append(numbers, number)
+				}
+				continue
+			}
+
+			switch word {
+			case "Tj", "TJ":
+				if len(operands) > 0 {
+					text.WriteString(operands[len(operands)-1])
+				}
+			case "'", "\"":
+				newline()
+				if len(operands) > 0 {
+					text.WriteString(operands[len(operands)-1])
+				}
+			case "BT":
+				// The first move in a text object is from the page origin
+				absolute = true
+			case "Td", "TD":
+				if len(numbers) < 2 {
+					break
+				}
+				y := numbers[len(numbers)-1]
+				if absolute {
+					moveTo(y)
+				} else if y != 0 {
+					newline()
+					lastY += y
+				} else {
+					space()
+				}
+				absolute = false
+			case "Tm":
+				if len(numbers) >= 6 {
+					moveTo(numbers[len(numbers)-1])
+				}
+				absolute = false
+			case "T*":
+				newline()
+			case "Tf":
+				font = fonts[name]
+			}
+			operands, numbers, name = nil, nil, ""
+		}
+	}
+	return text.String()
+}
+
+// literalString reads a (string) starting at content[i], returning its bytes
+// and the index after its closing parenthesis
+func literalString(content []byte, i int) ([]byte, int) {
+	var raw []byte
+	depth := 0
+	for i++; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\\' && i+1 < len(content):
+			i++
+			switch e := content[i]; e {
+			case 'n':
+				raw = append(raw, '\n')
+			case 'r':
+				raw = append(raw, '\r')
+			case 't':
+				raw = append(raw, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation
+				if e == '\r' && i+1 < len(content) && content[i+1] == '\n' {
+					i++
+				}
+			default:
+				if e >= '0' && e <= '7' {
+					value := 0
+					for n := 0; n < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7'; n++ {
+						value = value*8 + int(content[i]-'0')
+						i++
+					}
+					i--
+					raw = append(raw, byte(value))
+				} else {
+					raw = append(raw, e)
+				}
+			}
+		case c == '(':
+			depth++
+			raw = append(raw, c)
+		case c == ')':
+			if depth == 0 {
+				return raw, i + 1
+			}
+			depth--
+			raw = append(raw, c)
+		default:
+			raw = append(raw, c)
+		}
+	}
+	return raw, i
+}
+
+// hexString reads a <hex string> starting at content[i], returning its bytes
+// and the index after its closing bracket
+func hexString(content []byte, i int) ([]byte, int) {
+	end := bytes.IndexByte(content[i:], '>')
+	if end < 0 {
+		return nil, len(content)
+	}
+	digits := make([]byte, 0, end)
+	for _, c := range content[i+1 : i+end] {
+		if !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	raw := make([]byte, 0, len(digits)/2)
+	for j := 0; j+1 < len(digits); j += 2 {
+		value, err := strconv.ParseUint(string(digits[j:j+2]), 16, 8)
+		if err != nil {
+			return nil, i + end + 1
+		}
+		raw = append(raw, byte(value))
+	}
+	return raw, i + end + 1
+}
+
+// winAnsi maps the printable characters Windows-1252 puts in 0x80-0x9f
+var winAnsi = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8a: 'Š', 0x8b: '‹', 0x8c: 'Œ', 0x8e: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9a: 'š', 0x9b: '›', 0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+}
+
+// decodePDFString turns string bytes into text, dropping strings that are
+// glyph IDs of a font without a character map
+func decodePDFString(raw []byte) string {
+	if len(raw) >= 2 && raw[0] == 0xfe && raw[1] == 0xff {
+		return decodeUTF16(raw[2:])
+	}
+
+	var text strings.Builder
+	for _, b := range raw {
+		switch {
+		case b == '\t' || b == '\n' || b == '\r':
+			text.WriteByte(' ')
+		case b < 0x20:
+			// Control bytes are glyph IDs, which need the font's character map
+			return ""
+		case b >= 0x80 && b < 0xa0:
+			if r, ok := winAnsi[b]; ok {
+				text.WriteRune(r)
+			}
+		default:
+			text.WriteRune(rune(b))
+		}
+	}
+	return text.String()
+}
+
+// decodeUTF16 reads big-endian UTF-16 without a byte order mark
+func decodeUTF16(raw []byte) string {
+	units := make([]uint16, 0, len(raw)/2)
+	for j := 0; j+1 < len(raw); j += 2 {
+		units = append(units, uint16(raw[j])<<8|uint16(raw[j+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// toUnicode is a font's ToUnicode character map: the text behind each
+// character code of a given width
+type toUnicode struct {
+	width int
+	chars map[uint32]string
+}
+
+// decode reads string bytes as character codes, dropping codes the map
+// does not cover
+func (m *toUnicode) decode(raw []byte) string {
+	var text strings.Builder
+	for i := 0; i+m.width <= len(raw); i += m.width {
+		text.WriteString(m.chars[charCode(raw[i:i+m.width])])
+	}
+	return text.String()
+}
+
+func charCode(raw []byte) uint32 {
+	var code uint32
+	for _, b := range raw {
+		code = code<<8 | uint32(b)
+	}
+	return code
+}
+
+// cmapToken is a hex string, an array bracket or an operator in a CMap
+type cmapToken struct {
+	hex  []byte
+	word string
+}
+
+// parseCMap reads the codespace, bfchar and bfrange sections of a ToUnicode
+// CMap, returning nil when it maps nothing
+func parseCMap(content []byte) *toUnicode {
+	var tokens []cmapToken
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '<' && i+1 < len(content) && content[i+1] != '<':
+			raw, next := hexString(content, i)
+			tokens = append(tokens, cmapToken{hex: raw})
+			i = next
+		case c == '[' || c == ']':
+			tokens = append(tokens, cmapToken{word: string(c)})
+			i++
+		case c == '(':
+			_, i = literalString(content, i)
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case isPDFSpace(c) || isPDFDelimiter(c):
+			i++
+		default:
+			start := i
+			for i < len(content) && !isPDFSpace(content[i]) && !isPDFDelimiter(content[i]) {
+				i++
+			}
+			tokens = append(tokens, cmapToken{word: string(content[start:i])})
+		}
+	}
+
+	cmap := &toUnicode{chars: make(map[uint32]string)}
+	codes := 0
+	add := func(code []byte, text string) {
+		if cmap.width == 0 {
+			cmap.width = len(code)
+		}
+		if len(code) == cmap.width && codes < maxCMapCodes {
+			cmap.chars[charCode(code)] = text
+			codes++
+		}
+	}
+
+	section := ""
+	for i := 0; i < len(tokens); i++ {
+		switch token := tokens[i]; {
+		case strings.HasPrefix(token.word, "begin"):
+			section = token.word
+		case strings.HasPrefix(token.word, "end"):
+			section = ""
+		case token.hex == nil:
+		case section == "begincodespacerange":
+			if cmap.width == 0 && len(token.hex) > 0 {
+				cmap.width = len(token.hex)
+			}
+			i++ // the high end of the range
+		case section == "beginbfchar" && i+1 < len(tokens) && tokens[i+1].hex != nil:
+			add(token.hex, decodeUTF16(tokens[i+1].hex))
+			i++
+		case section == "beginbfrange" && i+2 < len(tokens) && tokens[i+1].hex != nil:
+			low, high := charCode(token.hex), charCode(tokens[i+1].hex)
+			valid := high >= low && high-low < maxCMapRange
+			code := bytes.Clone(token.hex)
+			i += 2
+			if tokens[i].word == "[" {
+				// Each code in the range has its own text
+				for i++; i < len(tokens) && tokens[i].word != "]"; i++ {
+					if valid && low <= high {
+						setCharCode(code, low)
+						add(code, decodeUTF16(tokens[i].hex))
+						low++
+					}
+				}
+				continue
+			}
+			// The text of the first code counts up through the range
+			text := []rune(decodeUTF16(tokens[i].hex))
+			if !valid || len(text) == 0 {
+				continue
+			}
+			for offset := uint32(0); offset <= high-low && codes < maxCMapCodes; offset++ {
+				setCharCode(code, low+offset)
+				last := len(text) - 1
+				add(code, string(text[:last])+string(text[last]+rune(offset)))
+			}
+		}
+	}
+
+	if len(cmap.chars) == 0 {
+		return nil
+	}
+	return cmap
+}
+
+// setCharCode writes code into raw as a big-endian number of len(raw) bytes
+func setCharCode(raw []byte, code uint32) {
+	for j := len(raw) - 1; j >= 0; j-- {
+		raw[j] = byte(code)
+		code >>= 8
+	}
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
@@ -0,0 +1,98 @@
+package resume
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContentTextMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"stray close paren", "BT (Hello) Tj ) ET", "Hello"},
+		{"stray close paren before operator", "BT ) (Hello) Tj ET", "Hello"},
+		{"truncated string", "BT (Hello", ""},
+		{"unterminated hex string", "BT <48656c6c6f", ""},
+		{"stray close bracket", "BT ] (Hi) Tj ET", "Hi"},
+		{"dictionary markers", "BT << /MCID 0 >> BDC (Hi) Tj EMC ET", "Hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan string, 1)
+			go func() { done <- contentText([]byte(tt.content), nil) }()
+			select {
+			case got := <-done:
+				if strings.TrimSpace(got) != tt.want {
+					t.Errorf("contentText(%q) = %q, want %q", tt.content, got, tt.want)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("contentText(%q) did not return", tt.content)
+			}
+		})
+	}
+}
+
+func TestExtractTextIdentityH(t *testing.T) {
+	// Laid out the way Chrome and Google Docs write a page: two Type0 fonts
+	// with Identity-H encoding whose strings are glyph IDs, each with a
+	// compressed ToUnicode map that mixes bfchar and bfrange entries
+	data, err := os.ReadFile("testdata/identity_h.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExtractText("resume.pdf", data)
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	want := "Jane Doe\nSOC Analyst – Safaricom, Nairobi\nCertified in Splunk & QRadar\n2021 – Present"
+	if got != want {
+		t.Errorf("ExtractText = %q, want %q", got, want)
+	}
+}
+
+func TestParseCMap(t *testing.T) {
+	cmap := parseCMap([]byte(`/CIDInit /ProcSet findresource begin
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0003> <0020>
+<004C> <00660069>
+endbfchar
+3 beginbfrange
+<0011> <0013> <0061>
+<0020> <0021> [<004A> <D83DDE00>]
+<0030> <0010> <0041>
+endbfrange
+endcmap`))
+	if cmap == nil || cmap.width != 2 {
+		t.Fatalf("parseCMap = %+v, want a two-byte map", cmap)
+	}
+
+	tests := map[string]string{
+		"\x00\x11\x00\x12\x00\x13": "abc",
+		"\x00\x4c\x00\x03\x00\x20": "fi J",
+		"\x00\x21":                 "😀",
+		"\x00\x30\x00\x11":         "a", // the reversed range maps nothing
+		"\x00\x11\x00":             "a", // a trailing half code is dropped
+	}
+	for raw, want := range tests {
+		if got := cmap.decode([]byte(raw)); got != want {
+			t.Errorf("decode(%q) = %q, want %q", raw, got, want)
+		}
+	}
+
+	if parseCMap([]byte("begincmap endcmap")) != nil {
+		t.Error("an empty CMap should map nothing")
+	}
+}
+
+func TestContentTextFonts(t *testing.T) {
+	fonts := map[string]*toUnicode{"F2": {width: 2, chars: map[uint32]string{0x24: "O", 0x25: "K"}}}
+	content := "BT /F1 12 Tf 72 712 Td (Plain) Tj /F2 12 Tf 72 698 Td <00240025> Tj /F1 12 Tf 72 684 Td (Again) Tj ET"
+	if got := contentText([]byte(content), fonts); got != "Plain\nOK\nAgain" {
+		t.Errorf("contentText = %q", got)
+	}
+}
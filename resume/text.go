@@ -0,0 +1,150 @@
+// Package resume reads uploaded resumes: it pulls the text out of PDF, DOCX
+// and plain text files and finds the positions and experience they list.
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file type; upload a PDF, DOCX or plain text file")
+	ErrNoText            = errors.New("no text found in the file; if it is a scanned or image-only PDF, upload a DOCX or text version")
+)
+
+// maxDocumentXML caps how much of a DOCX is decompressed
+const maxDocumentXML = 20 << 20
+
+// ExtractText returns the text of a resume. The format is detected from the
+// file's contents, falling back to the extension for plain text.
+func ExtractText(filename string, data []byte) (string, error) {
+	var text string
+	var err error
+
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF")):
+		text, err = pdfText(data)
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		text, err = docxText(data)
+	case isPlainText(filename, data):
+		text = string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	default:
+		return "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = normalizeText(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// ContentType is the MIME type to serve a stored resume with
+func ContentType(filename string, data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF")):
+		return "application/pdf"
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	case strings.EqualFold(filepath.Ext(filename), ".md"):
+		return "text/markdown; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+func isPlainText(filename string, data []byte) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt", ".md", ".text", "":
+	default:
+		return false
+	}
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
+
+// docxText reads the paragraphs of word/document.xml
+func docxText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("could not open the DOCX file: %v", err)
+	}
+
+	var document *zip.File
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			document = file
+			break
+		}
+	}
+	if document == nil {
+		return "", ErrUnsupportedFormat
+	}
+
+	reader, err := document.Open()
+	if err != nil {
+		return "", fmt.Errorf("could not open the DOCX file: %v", err)
+	}
+	defer reader.Close()
+
+	var text strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxDocumentXML))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not read the DOCX file: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString("\t")
+			case "br", "cr":
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return text.String(), nil
+}
+
+var (
+	trailingSpacePattern = regexp.MustCompile(`[ \t]+\n`)
+	blankLinesPattern    = regexp.MustCompile(`\n{3,}`)
+)
+
+func normalizeText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.ReplaceAll(text, "\u00a0", " ")
+	text = trailingSpacePattern.ReplaceAllString(text, "\n")
+	text = blankLinesPattern.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
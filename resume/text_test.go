@@ -0,0 +1,139 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testPDF wraps a content stream in a minimal PDF, compressing it with Flate
+// when compress is set
+func testPDF(content string, compress bool) []byte {
+	header := fmt.Sprintf("<< /Length %d >>", len(content))
+	body := content
+	if compress {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write([]byte(content))
+		zw.Close()
+		header = fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>", compressed.Len())
+		body = compressed.String()
+	}
+	return []byte("%PDF-1.4\n1 0 obj\n" + header + "\nstream\n" + body + "\nendstream\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")
+}
+
+// testDOCX builds a DOCX holding the given files
+func testDOCX(files map[string]string) []byte {
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return out.Bytes()
+}
+
+const testDocumentXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:r><w:t>Jane Doe</w:t></w:r></w:p>
+<w:p><w:r><w:t>SOC Analyst</w:t><w:tab/><w:t xml:space="preserve">Mar 2021 - Present</w:t></w:r></w:p>
+<w:p><w:r><w:t>Splunk &amp; QRadar</w:t><w:br/><w:t>Nairobi</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+func TestExtractText(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     string
+	}{
+		{
+			name:     "pdf",
+			filename: "resume.pdf",
+			data:     testPDF("BT /F1 12 Tf 72 712 Td (Jane Doe) Tj 0 -14 Td (SOC Analyst) Tj ET", false),
+			want:     "Jane Doe\nSOC Analyst",
+		},
+		{
+			name:     "compressed pdf",
+			filename: "resume.pdf",
+			data:     testPDF("BT /F1 12 Tf 72 712 Td [(Jane) -300 (Doe)] TJ 0 -14 Td (Nairobi \\(remote\\)) Tj ET", true),
+			want:     "Jane Doe\nNairobi (remote)",
+		},
+		{
+			name:     "pdf with utf-16 and hex strings",
+			filename: "resume.pdf",
+			data:     testPDF("BT 72 712 Td <FEFF004A0061006E0065> Tj 72 698 Td <4E6169726F6269> Tj ET", false),
+			want:     "Jane\nNairobi",
+		},
+		{
+			name:     "pdf with windows-1252 punctuation",
+			filename: "resume.pdf",
+			data:     testPDF("BT 72 712 Td (\x80 50 \x96 \x93quoted\x94 \x8aibenik) Tj ET", false),
+			want:     "€ 50 – “quoted” Šibenik",
+		},
+		{
+			name:     "pdf detected without an extension",
+			filename: "upload",
+			data:     testPDF("BT 72 712 Td (Jane Doe) Tj ET", false),
+			want:     "Jane Doe",
+		},
+		{
+			name:     "docx",
+			filename: "resume.docx",
+			data:     testDOCX(map[string]string{"word/document.xml": testDocumentXML}),
+			want:     "Jane Doe\nSOC Analyst\tMar 2021 - Present\nSplunk & QRadar\nNairobi",
+		},
+		{
+			name:     "plain text",
+			filename: "resume.txt",
+			data:     []byte("\xef\xbb\xbfJane Doe  \r\n\r\n\r\n\r\nSOC Analyst at Safaricom\r\n"),
+			want:     "Jane Doe\n\nSOC Analyst at Safaricom",
+		},
+		{
+			name:     "markdown",
+			filename: "resume.md",
+			data:     []byte("# Jane Doe\n\n- SOC Analyst\n"),
+			want:     "# Jane Doe\n\n- SOC Analyst",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractText(tt.filename, tt.data)
+			if err != nil {
+				t.Fatalf("ExtractText: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractTextErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     error
+	}{
+		{"image only pdf", "scan.pdf", testPDF("q 595 0 0 842 0 0 cm /Im1 Do Q", false), ErrNoText},
+		{"glyph ids without a character map", "resume.pdf", testPDF("BT 72 712 Td <00120034> Tj ET", false), ErrNoText},
+		{"docx without a document", "resume.docx", testDOCX(map[string]string{"word/styles.xml": "<styles/>"}), ErrUnsupportedFormat},
+		{"empty text file", "resume.txt", []byte(" \n\n "), ErrNoText},
+		{"binary", "resume.txt", []byte("MZ\x00\x90\x00"), ErrUnsupportedFormat},
+		{"other extension", "resume.rtf", []byte(`{\rtf1 Jane Doe}`), ErrUnsupportedFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExtractText(tt.filename, tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("ExtractText error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// Exclusion rule kinds
+const (
+	RuleKeyword   = "keyword"
+	RuleCompany   = "company"
+	RuleTitle     = "title"
+	RuleMinSalary = "min_salary"
+)
+
+// Exclusion rule actions, and the filter state they leave on a job
+const (
+	ActionDrop   = "drop"
+	ActionDemote = "demote"
+
+	FilterDropped = "dropped"
+	FilterDemoted = "demoted"
+)
+
+// demotePenalty is taken off the score of a job matching a demote rule
+const demotePenalty = 30
+
+// ExclusionRules are the user's deal-breakers, with their patterns compiled
+// once so they can be checked against many jobs
+type ExclusionRules []exclusionRule
+
+type exclusionRule struct {
+	models.ExclusionRule
+	pattern   *regexp.Regexp // keyword and title rules
+	minSalary int            // min_salary rules
+}
+
+// CompileExclusionRules prepares rules for ApplyExclusionRules. Rules that
+// fail to compile never match; ValidateExclusionRule keeps them out.
+func CompileExclusionRules(rules []models.ExclusionRule) ExclusionRules {
+	compiled := make(ExclusionRules, 0, len(rules))
+	for _, rule := range rules {
+		c := exclusionRule{ExclusionRule: rule}
+		switch rule.Kind {
+		case RuleKeyword:
+			c.pattern = keywordPattern(rule.Value)
+		case RuleTitle:
+			c.pattern, _ = regexp.Compile("(?i)" + rule.Value)
+		case RuleMinSalary:
+			c.minSalary, _ = strconv.Atoi(rule.Value)
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled
+}
+
+// keywordPattern matches keyword as a whole word, ignoring case. \b needs a
+// word character on the inside, so it can't bound keywords such as "c++",
+// "c#" or ".net"; instead a keyword that starts with a word character can't
+// follow one or a dot, and one that ends with a word character can't be
+// followed by one, "+" or "#". That keeps "c" out of "C++" and "C#", and
+// "net" out of ".NET".
+func keywordPattern(keyword string) *regexp.Regexp {
+	before, after := `(^|\W)`, `(\W|$)`
+	if keyword != "" && isWordByte(keyword[0]) {
+		before = `(^|[^\w.])`
+	}
+	if keyword != "" && isWordByte(keyword[len(keyword)-1]) {
+		after = `($|[^\w+#])`
+	}
+	return regexp.MustCompile(`(?i)` + before + regexp.QuoteMeta(keyword) + after)
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// ApplyExclusionRules checks a scored job against the user's deal-breakers.
+// A matching drop rule marks the job as dropped, which hides it from
+// listings; demote rules keep it visible but take demotePenalty points off.
+// The reasons are recorded on the job either way.
+func (s *RealScraper) ApplyExclusionRules(job *models.Job, breakdown *models.ScoreBreakdown, rules ExclusionRules) {
+	job.FilterAction = ""
+	reasons := []string{}
+
+	for _, rule := range rules {
+		reason, ok := s.matchExclusionRule(job, rule)
+		if !ok {
+			continue
+		}
+		reasons = append(reasons, reason)
+
+		if rule.Action == ActionDrop {
+			job.FilterAction = FilterDropped
+		} else if job.FilterAction == "" {
+			job.FilterAction = FilterDemoted
+		}
+	}
+	job.FilterReasons = s.ConvertToJSON(reasons)
+
+	if job.FilterAction == FilterDemoted {
+		penalty := min(demotePenalty, breakdown.Total)
+		breakdown.Components = append(breakdown.Components, models.ScoreComponent{
+			Name:   "Demoted",
+			Points: -penalty,
+			Rule:   strings.Join(reasons, "; "),
+		})
+		breakdown.Total -= penalty
+	}
+}
+
+// ValidateExclusionRule reports why a rule cannot be used, or nil
+func ValidateExclusionRule(rule models.ExclusionRule) error {
+	if strings.TrimSpace(rule.Value) == "" {
+		return fmt.Errorf("a value is required")
+	}
+	if rule.Action != ActionDrop && rule.Action != ActionDemote {
+		return fmt.Errorf("action must be drop or demote")
+	}
+
+	switch rule.Kind {
+	case RuleKeyword, RuleCompany:
+		return nil
+	case RuleTitle:
+		if _, err := regexp.Compile("(?i)" + rule.Value); err != nil {
+			return fmt.Errorf("invalid title pattern: %v", err)
+		}
+		return nil
+	case RuleMinSalary:
+		if n, err := strconv.Atoi(rule.Value); err != nil || n <= 0 {
+			return fmt.Errorf("minimum salary must be a positive whole number")
+		}
+		return nil
+	default:
+		return fmt.Errorf("kind must be keyword, company, title or min_salary")
+	}
+}
+
+func (s *RealScraper) matchExclusionRule(job *models.Job, rule exclusionRule) (string, bool) {
+	switch rule.Kind {
+	case RuleKeyword:
+		if rule.pattern.MatchString(job.Title + " " + job.Description) {
+			return fmt.Sprintf("mentions %q", rule.Value), true
+		}
+
+	case RuleCompany:
+		if strings.Contains(strings.ToLower(job.Company), strings.ToLower(rule.Value)) {
+			return fmt.Sprintf("company matches %q", rule.Value), true
+		}
+
+	case RuleTitle:
+		if rule.pattern != nil && rule.pattern.MatchString(job.Title) {
+			return fmt.Sprintf("title matches %q", rule.Value), true
+		}
+
+	case RuleMinSalary:
+		salary := s.ExtractSalary(job.Description)
+		if amount := statedSalaryAmount(salary); amount > 0 && amount < rule.minSalary {
+			return fmt.Sprintf("salary %s is below KSh %d", salary, rule.minSalary), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,63 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+func TestKeywordRuleBoundaries(t *testing.T) {
+	tests := []struct {
+		keyword string
+		text    string
+		want    bool
+	}{
+		{"c++", "Embedded developer writing C++ firmware", true},
+		{"c++", "C++", true},
+		{"c#", "Backend role using C#, .NET and SQL Server", true},
+		{".net", "Backend role using C#, .NET and SQL Server", true},
+		{"sales", "Sales engineer", true},
+		{"sales", "Salesforce administrator", false},
+		{"java", "JavaScript developer", false},
+		{"night shift", "Rotating night shift required", true},
+		{"c", "Embedded developer writing C++ firmware", false},
+		{"c", "Backend role using C#, .NET and SQL Server", false},
+		{"c", "Firmware in C and Rust", true},
+		{"net", "Backend role using C#, .NET and SQL Server", false},
+		{"net", "Salary is paid net of tax", true},
+	}
+	s := &RealScraper{}
+	for _, tt := range tests {
+		job := &models.Job{Title: "Engineer", Description: tt.text}
+		rules := CompileExclusionRules([]models.ExclusionRule{{Kind: RuleKeyword, Value: tt.keyword}})
+		if _, got := s.matchExclusionRule(job, rules[0]); got != tt.want {
+			t.Errorf("keyword %q in %q = %v, want %v", tt.keyword, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestApplyExclusionRules(t *testing.T) {
+	rules := CompileExclusionRules([]models.ExclusionRule{
+		{Kind: RuleTitle, Value: "^sales", Action: ActionDrop},
+		{Kind: RuleCompany, Value: "acme", Action: ActionDemote},
+		{Kind: RuleTitle, Value: "([", Action: ActionDrop},
+	})
+	s := &RealScraper{}
+
+	tests := []struct {
+		job    models.Job
+		action string
+		total  int
+	}{
+		{models.Job{Title: "Sales Engineer", Company: "Initech"}, FilterDropped, 80},
+		{models.Job{Title: "SOC Analyst", Company: "ACME Bank"}, FilterDemoted, 50},
+		{models.Job{Title: "SOC Analyst", Company: "Initech"}, "", 80},
+	}
+	for _, tt := range tests {
+		breakdown := models.ScoreBreakdown{Total: 80}
+		s.ApplyExclusionRules(&tt.job, &breakdown, rules)
+		if tt.job.FilterAction != tt.action || breakdown.Total != tt.total {
+			t.Errorf("%s at %s: action %q, total %d; want %q, %d", tt.job.Title, tt.job.Company, tt.job.FilterAction, breakdown.Total, tt.action, tt.total)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// freshnessHalfLife is how many days it takes a job's ranking score to halve
+const freshnessHalfLife = 21.0
+
+// JobAge returns how many days ago the job was posted. The earlier of the
+// stored posting date and when the job was first seen is used, because
+// re-scraping a listing without a real posting date resets the former.
+func JobAge(job *models.Job, now time.Time) float64 {
+	posted := job.CreatedAt
+	if t, err := time.Parse("2006-01-02", job.PostedDate); err == nil && (posted.IsZero() || t.Before(posted)) {
+		posted = t
+	}
+	if posted.IsZero() {
+		return 0
+	}
+	return max(0, now.Sub(posted).Hours()/24)
+}
+
+// FreshnessRank is the job's score decayed by age, so a week-old 85 can
+// outrank a three-month-old 90. Jobs closing within a week get a boost of up
+// to 25% as the deadline approaches; jobs past their deadline rank last.
+func FreshnessRank(job *models.Job, now time.Time) float64 {
+	rank := float64(job.Score) * math.Pow(0.5, JobAge(job, now)/freshnessHalfLife)
+
+	if deadline, err := time.Parse("2006-01-02", job.ApplicationDeadline); err == nil {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		daysLeft := deadline.Sub(today).Hours() / 24
+		switch {
+		case daysLeft < 0:
+			return 0
+		case daysLeft <= 7:
+			rank *= 1 + 0.25*(7-daysLeft)/7
+		}
+	}
+	return rank
+}
+
+// SortByFreshness orders jobs by FreshnessRank, highest first
+func SortByFreshness(jobs []models.Job, now time.Time) {
+	ranks := make(map[string]float64, len(jobs))
+	for i := range jobs {
+		ranks[jobs[i].ID] = FreshnessRank(&jobs[i], now)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return ranks[jobs[i].ID] > ranks[jobs[j].ID]
+	})
+}
@@ -0,0 +1,154 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/geo"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// attributePattern maps a regular expression to the normalized value it implies
+type attributePattern struct {
+	re    *regexp.Regexp
+	value string
+}
+
+var (
+	// Order matters: the first match wins, so more specific types come first
+	// ("full-time internship" is an internship).
+	employmentTypePatterns = []attributePattern{
+		{regexp.MustCompile(`\binternships?\b|\bintern\b|\battachment\b|graduate trainee`), "Internship"},
+		{regexp.MustCompile(`\bcontractor\b|fixed[- ]term|\bon (a )?contract\b|\bcontract (role|position|basis|job|employment)\b|\bconsultancy\b`), "Contract"},
+		{regexp.MustCompile(`part[- ]time`), "Part-time"},
+		{regexp.MustCompile(`\btemporary\b|\blocum\b`), "Temporary"},
+		{regexp.MustCompile(`full[- ]time|\bpermanent\b`), "Full-time"},
+	}
+
+	workModePatterns = []attributePattern{
+		{regexp.MustCompile(`\bhybrid\b`), "Hybrid"},
+		{regexp.MustCompile(`\b(fully )?remote (role|position|job|work|working|opportunity|first)\b|\bwork(ing)? remotely\b|work from home|\bwfh\b|\(remote\)`), "Remote"},
+		{regexp.MustCompile(`\bon[- ]?site\b|\bin[- ]office\b|office[- ]based|\bin[- ]person\b`), "Onsite"},
+	}
+
+	schemaEmploymentTypes = map[string]string{
+		"FULL_TIME":  "Full-time",
+		"PART_TIME":  "Part-time",
+		"CONTRACTOR": "Contract",
+		"TEMPORARY":  "Temporary",
+		"INTERN":     "Internship",
+		"VOLUNTEER":  "Volunteer",
+	}
+
+	deadlineDate    = `(\d{4}-\d{1,2}-\d{1,2}|\d{1,2}[/.-]\d{1,2}[/.-]\d{2,4}|\d{1,2}(?:st|nd|rd|th)?\s+(?:of\s+)?[a-z]{3,9},?\s+\d{4}|[a-z]{3,9}\s+\d{1,2}(?:st|nd|rd|th)?,?\s+\d{4})`
+	deadlinePattern = regexp.MustCompile(`(?:deadline|closing date|apply (?:by|before)|applications? close[sd]?|closes)(?:\s+(?:is|on|for applications))?\s*[:\-–]?\s*` + deadlineDate)
+	ordinalSuffix   = regexp.MustCompile(`(\d)(st|nd|rd|th)\b`)
+
+	deadlineLayouts = []string{
+		"2006-1-2",
+		"2/1/2006", "2-1-2006", "2.1.2006",
+		"2/1/06", "2-1-06", "2.1.06",
+		"2 January 2006", "2 Jan 2006",
+		"January 2 2006", "Jan 2 2006",
+	}
+)
+
+// ExtractEmploymentType returns Full-time, Part-time, Contract, Temporary,
+// Internship or Volunteer, preferring the page's structured data over text.
+func (s *RealScraper) ExtractEmploymentType(text string, posting *JobPostingData) string {
+	if posting != nil {
+		for _, t := range posting.EmploymentTypes {
+			if value, ok := schemaEmploymentTypes[strings.ToUpper(strings.ReplaceAll(t, "-", "_"))]; ok {
+				return value
+			}
+		}
+	}
+
+	return matchAttribute(employmentTypePatterns, strings.ToLower(text))
+}
+
+// ExtractWorkMode returns Remote, Hybrid or Onsite
+func (s *RealScraper) ExtractWorkMode(text, location string, posting *JobPostingData) string {
+	if posting != nil && strings.EqualFold(posting.JobLocationType, "TELECOMMUTE") {
+		return "Remote"
+	}
+
+	locationLower := strings.ToLower(location)
+	switch {
+	case strings.Contains(locationLower, "hybrid"):
+		return "Hybrid"
+	case strings.Contains(locationLower, "remote"):
+		return "Remote"
+	}
+
+	return matchAttribute(workModePatterns, strings.ToLower(text))
+}
+
+// ExtractDeadline returns the application closing date as YYYY-MM-DD, or an
+// empty string when none is stated.
+func (s *RealScraper) ExtractDeadline(text string, posting *JobPostingData) string {
+	if posting != nil && posting.ValidThrough != "" {
+		if deadline := parseDeadline(posting.ValidThrough); deadline != "" {
+			return deadline
+		}
+	}
+
+	matches := deadlinePattern.FindStringSubmatch(strings.ToLower(text))
+	if len(matches) < 2 {
+		return ""
+	}
+	return parseDeadline(matches[1])
+}
+
+func matchAttribute(patterns []attributePattern, text string) string {
+	for _, pattern := range patterns {
+		if pattern.re.MatchString(text) {
+			return pattern.value
+		}
+	}
+	return ""
+}
+
+func parseDeadline(raw string) string {
+	raw = strings.TrimSpace(raw)
+
+	// ISO timestamps from structured data
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.Format("2006-01-02")
+	}
+	if len(raw) > 10 {
+		if t, err := time.Parse("2006-01-02", raw[:10]); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+
+	cleaned := ordinalSuffix.ReplaceAllString(raw, "$1")
+	cleaned = strings.ReplaceAll(cleaned, ",", "")
+	cleaned = strings.ReplaceAll(cleaned, " of ", " ")
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	for _, layout := range deadlineLayouts {
+		if t, err := time.Parse(layout, cleaned); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+// NormalizeJobLocation resolves the job's free-text location against the
+// offline gazetteer and fills in city, region, country and coordinates.
+func (s *RealScraper) NormalizeJobLocation(job *models.Job) {
+	place, ok := geo.Normalize(job.Location)
+	if !ok {
+		return
+	}
+
+	job.City = place.City
+	job.Region = place.Region
+	job.Country = place.Country
+	if place.HasCoordinates() {
+		job.Latitude = place.Latitude
+		job.Longitude = place.Longitude
+	}
+}
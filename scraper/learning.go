@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/feedback"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// feedbackModel returns the model trained on liked, applied and dismissed
+// jobs, training it on first use. It is nil until enough jobs are rated.
+func (s *RealScraper) feedbackModel() *feedback.Model {
+	s.feedbackMu.Lock()
+	defer s.feedbackMu.Unlock()
+
+	if !s.feedbackTrained {
+		model, err := s.trainFeedbackModel()
+		if err != nil {
+			log.Printf("⚠️ Error training feedback model: %v", err)
+		}
+		s.feedback, s.feedbackTrained = model, true
+	}
+	return s.feedback
+}
+
+// RetrainFeedbackModel refits the feedback model to the current ratings
+func (s *RealScraper) RetrainFeedbackModel() error {
+	model, err := s.trainFeedbackModel()
+	if err != nil {
+		return err
+	}
+
+	s.feedbackMu.Lock()
+	s.feedback, s.feedbackTrained = model, true
+	s.feedbackMu.Unlock()
+	return nil
+}
+
+func (s *RealScraper) trainFeedbackModel() (*feedback.Model, error) {
+	jobs, signals, err := s.db.GetRatedJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	examples := make([]feedback.Example, len(jobs))
+	for i := range jobs {
+		examples[i] = feedback.Example{Features: feedback.Features(&jobs[i])}
+		if signals[i] != feedback.Dismissed {
+			examples[i].Label = 1
+		}
+	}
+	return feedback.Train(examples), nil
+}
+
+// calculateFeedbackScore is the model's predicted interest in the job. It
+// returns false while too few jobs have been rated to train the model.
+func (s *RealScraper) calculateFeedbackScore(job *models.Job) (float64, string, bool) {
+	model := s.feedbackModel()
+	if model == nil {
+		return 0, "", false
+	}
+
+	features := feedback.Features(job)
+	probability := model.Predict(features)
+	rule := fmt.Sprintf("%.0f%% predicted interest from %d liked and %d dismissed jobs",
+		probability*100, model.Positives, model.Negatives)
+	if reasons := model.Explain(features, 4); len(reasons) > 0 {
+		rule += " (" + strings.Join(reasons, ", ") + ")"
+	}
+	return probability, rule, true
+}
@@ -0,0 +1,33 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/feedback"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+func TestCalculateFeedbackScore(t *testing.T) {
+	job := &models.Job{Title: "SOC Analyst", WorkMode: "remote"}
+
+	// Too few ratings leaves the component out
+	s := &RealScraper{feedbackTrained: true}
+	if _, _, ok := s.calculateFeedbackScore(job); ok {
+		t.Error("scored feedback before the model was trained")
+	}
+
+	s.feedback = feedback.Train([]feedback.Example{
+		{Features: feedback.Features(&models.Job{Title: "SOC Engineer", WorkMode: "remote"}), Label: 1},
+		{Features: feedback.Features(&models.Job{Title: "Security Analyst", WorkMode: "remote"}), Label: 1},
+		{Features: feedback.Features(&models.Job{Title: "Sales Executive", WorkMode: "on-site"}), Label: 0},
+		{Features: feedback.Features(&models.Job{Title: "Sales Manager", WorkMode: "on-site"}), Label: 0},
+	})
+	fraction, rule, ok := s.calculateFeedbackScore(job)
+	if !ok || fraction <= 0.5 || fraction > 1 {
+		t.Errorf("calculateFeedbackScore = %v, %v", fraction, ok)
+	}
+	if !strings.Contains(rule, "from 2 liked and 2 dismissed jobs") {
+		t.Errorf("rule = %q", rule)
+	}
+}
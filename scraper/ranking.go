@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/relevance"
+)
+
+// relevanceIndex returns the BM25 index over every stored job, building it
+// from the database on first use
+func (s *RealScraper) relevanceIndex() *relevance.Index {
+	s.relevanceMu.Lock()
+	defer s.relevanceMu.Unlock()
+
+	if s.relevance == nil {
+		index, err := s.buildRelevanceIndex()
+		if err != nil {
+			log.Printf("⚠️ Error building relevance index: %v", err)
+		}
+		s.relevance = index
+	}
+	return s.relevance
+}
+
+// RebuildRelevanceIndex re-reads every stored job into a fresh index, so
+// document frequencies match the jobs currently in the database
+func (s *RealScraper) RebuildRelevanceIndex() error {
+	index, err := s.buildRelevanceIndex()
+	if err != nil {
+		return err
+	}
+
+	s.relevanceMu.Lock()
+	s.relevance = index
+	s.relevanceMu.Unlock()
+	return nil
+}
+
+func (s *RealScraper) buildRelevanceIndex() (*relevance.Index, error) {
+	index := relevance.NewIndex()
+	lastID := ""
+	for {
+		jobs, err := s.db.GetJobsAfter(lastID, rescoreBatchSize)
+		if err != nil {
+			return index, err
+		}
+		if len(jobs) == 0 {
+			return index, nil
+		}
+		for i := range jobs {
+			index.Add(relevanceKey(&jobs[i]), relevanceText(&jobs[i]))
+		}
+		lastID = jobs[len(jobs)-1].ID
+	}
+}
+
+// relevanceKey identifies a job in the index. The URL is used because a
+// re-scraped job gets a fresh ID before being upserted onto the stored one.
+func relevanceKey(job *models.Job) string {
+	if job.URL != "" {
+		return job.URL
+	}
+	return job.ID
+}
+
+func relevanceText(job *models.Job) string {
+	return job.Title + "\n" + job.Description
+}
+
+// calculateRelevanceScore ranks the job against the user's profile text with
+// BM25. The result is relative to the best-matching stored job, so it picks
+// up jobs that use the profile's vocabulary without naming a listed skill.
+func (s *RealScraper) calculateRelevanceScore(job *models.Job, profileText string) (float64, string) {
+	query := relevance.NewQuery(profileText)
+	if len(query) == 0 {
+		return 0, "profile text has no searchable terms"
+	}
+
+	index := s.relevanceIndex()
+	key := relevanceKey(job)
+	if !index.Contains(key) {
+		index.Add(key, relevanceText(job))
+	}
+
+	match := index.Score(query, key)
+	best := index.MaxScore(query)
+	if match.Score == 0 || best == 0 {
+		return 0, "no profile terms found in the posting"
+	}
+
+	terms := match.Terms
+	if len(terms) > 5 {
+		terms = terms[:5]
+	}
+	fraction := minFloat(match.Score/best, 1)
+	return fraction, fmt.Sprintf("%d profile terms found (%s); %.0f%% of the best-matching job",
+		len(match.Terms), strings.Join(terms, ", "), fraction*100)
+}
@@ -0,0 +1,376 @@
+package scraper
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MainContent is the cleaned main body of a job detail page
+type MainContent struct {
+	Text string // Markdown-style text: "## " headings, "- " bullets, blank lines between paragraphs
+	HTML string // sanitized HTML restricted to structural tags
+}
+
+var (
+	// Elements that never contain the job description
+	boilerplateTags = map[atom.Atom]bool{
+		atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Iframe: true,
+		atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+		atom.Form: true, atom.Button: true, atom.Svg: true, atom.Select: true,
+		atom.Input: true, atom.Textarea: true, atom.Label: true, atom.Img: true,
+		atom.Picture: true, atom.Video: true, atom.Audio: true, atom.Canvas: true,
+		atom.Link: true, atom.Meta: true, atom.Template: true,
+	}
+
+	unlikelyCandidate = regexp.MustCompile(`(?i)cookie|consent|gdpr|banner|navbar|\bnav\b|menu|footer|header|sidebar|breadcrumb|social|share|comment|newsletter|subscribe|signup|sign-up|login|modal|popup|advert|\bads?\b|sponsor|related|recommend|similar|pagination|widget|promo`)
+	likelyCandidate   = regexp.MustCompile(`(?i)description|job|content|article|main|body|post|detail|vacanc|requirement|responsibilit`)
+
+	// Elements whose text counts as content when scoring candidates
+	scoredTags = map[atom.Atom]bool{
+		atom.P: true, atom.Li: true, atom.Pre: true, atom.Td: true, atom.Blockquote: true,
+	}
+
+	blockTags = map[atom.Atom]bool{
+		atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+		atom.Blockquote: true, atom.Pre: true, atom.Table: true, atom.Tr: true, atom.Dl: true,
+		atom.Dt: true, atom.Dd: true,
+	}
+
+	headingLevels = map[atom.Atom]int{
+		atom.H1: 2, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 4, atom.H6: 4,
+	}
+
+	inlineKeepTags = map[atom.Atom]string{
+		atom.Strong: "strong", atom.B: "strong", atom.Em: "em", atom.I: "em", atom.Code: "code",
+	}
+
+	spaceRun = regexp.MustCompile(`[ \t\r\n\f]+`)
+)
+
+// minContentLength below which a candidate is considered too thin and the
+// whole cleaned body is used instead
+const minContentLength = 140
+
+// ExtractMainContent picks the element most likely to hold the job
+// description (readability-style: paragraph density, class/id hints, link
+// density), strips navigation, cookie banners and other boilerplate, and
+// renders it as structured text and sanitized HTML.
+func ExtractMainContent(page []byte) MainContent {
+	doc, err := xhtml.Parse(bytes.NewReader(page))
+	if err != nil {
+		return MainContent{}
+	}
+
+	body := findFirst(doc, atom.Body)
+	if body == nil {
+		body = doc
+	}
+	removeBoilerplate(body)
+
+	best := bestCandidate(body)
+	if best == nil || len(innerText(best)) < minContentLength {
+		best = body
+	}
+
+	r := &contentRenderer{}
+	r.render(best)
+	return MainContent{
+		Text: r.text(),
+		HTML: strings.TrimSpace(r.html.String()),
+	}
+}
+
+func removeBoilerplate(n *xhtml.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == xhtml.CommentNode || (c.Type == xhtml.ElementNode && isBoilerplate(c)) {
+			n.RemoveChild(c)
+		} else {
+			removeBoilerplate(c)
+		}
+		c = next
+	}
+}
+
+func isBoilerplate(n *xhtml.Node) bool {
+	if boilerplateTags[n.DataAtom] {
+		return true
+	}
+	if strings.EqualFold(attr(n, "aria-hidden"), "true") || strings.Contains(attr(n, "style"), "display:none") {
+		return true
+	}
+	if role := attr(n, "role"); role == "navigation" || role == "banner" || role == "contentinfo" || role == "dialog" {
+		return true
+	}
+
+	hints := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyCandidate.MatchString(hints) && !likelyCandidate.MatchString(hints)
+}
+
+// bestCandidate returns the highest-scoring container. Candidates are kept in
+// the order the walk first reaches them, so equal scores always resolve to
+// the earlier element and the same page always gives the same description.
+func bestCandidate(root *xhtml.Node) *xhtml.Node {
+	scores := make(map[*xhtml.Node]float64)
+	var candidates []*xhtml.Node
+
+	initialize := func(n *xhtml.Node) {
+		if _, ok := scores[n]; ok {
+			return
+		}
+		score := 0.0
+		switch n.DataAtom {
+		case atom.Article, atom.Main:
+			score += 10
+		case atom.Div, atom.Section:
+			score += 5
+		case atom.Ul, atom.Ol, atom.Table:
+			score -= 3
+		}
+		hints := attr(n, "class") + " " + attr(n, "id")
+		if likelyCandidate.MatchString(hints) {
+			score += 25
+		}
+		scores[n] = score
+		candidates = append(candidates, n)
+	}
+
+	var walk func(n *xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		if n.Type == xhtml.ElementNode && scoredTags[n.DataAtom] && n.Parent != nil {
+			text := innerText(n)
+			if len(text) >= 25 {
+				contentScore := 1 + float64(strings.Count(text, ",")) + minFloat(float64(len(text))/100, 3)
+
+				initialize(n.Parent)
+				scores[n.Parent] += contentScore
+				if gp := n.Parent.Parent; gp != nil && gp.Type == xhtml.ElementNode {
+					initialize(gp)
+					scores[gp] += contentScore / 2
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	var best *xhtml.Node
+	bestScore := 0.0
+	for _, n := range candidates {
+		if n.DataAtom == atom.Li || n.DataAtom == atom.Tr {
+			continue
+		}
+		score := scores[n] * (1 - linkDensity(n))
+		if best == nil || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	return best
+}
+
+func linkDensity(n *xhtml.Node) float64 {
+	total := len(innerText(n))
+	if total == 0 {
+		return 0
+	}
+
+	linkLength := 0
+	var walk func(*xhtml.Node)
+	walk = func(c *xhtml.Node) {
+		if c.Type == xhtml.ElementNode && c.DataAtom == atom.A {
+			linkLength += len(innerText(c))
+			return
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return float64(linkLength) / float64(total)
+}
+
+// contentRenderer writes the Markdown-style text and sanitized HTML in one pass
+type contentRenderer struct {
+	lines    []string
+	current  strings.Builder
+	html     strings.Builder
+	listKind []atom.Atom
+	listNum  []int
+}
+
+func (r *contentRenderer) render(n *xhtml.Node) {
+	switch n.Type {
+	case xhtml.TextNode:
+		text := spaceRun.ReplaceAllString(n.Data, " ")
+		if strings.TrimSpace(text) == "" && r.current.Len() == 0 {
+			return
+		}
+		r.current.WriteString(text)
+		r.html.WriteString(html.EscapeString(text))
+		return
+	case xhtml.ElementNode:
+	default:
+		r.renderChildren(n)
+		return
+	}
+
+	switch {
+	case headingLevels[n.DataAtom] > 0:
+		level := headingLevels[n.DataAtom]
+		r.endLine()
+		r.html.WriteString(headingOpen(level))
+		r.current.WriteString(strings.Repeat("#", level) + " ")
+		r.renderChildren(n)
+		r.html.WriteString(headingClose(level))
+		r.endBlock()
+
+	case n.DataAtom == atom.Ul || n.DataAtom == atom.Ol:
+		r.endLine()
+		tag := "ul"
+		if n.DataAtom == atom.Ol {
+			tag = "ol"
+		}
+		r.listKind = append(r.listKind, n.DataAtom)
+		r.listNum = append(r.listNum, 0)
+		r.html.WriteString("<" + tag + ">")
+		r.renderChildren(n)
+		r.html.WriteString("</" + tag + ">")
+		r.listKind = r.listKind[:len(r.listKind)-1]
+		r.listNum = r.listNum[:len(r.listNum)-1]
+		r.endBlock()
+
+	case n.DataAtom == atom.Li:
+		r.endLine()
+		depth := len(r.listKind)
+		marker := "- "
+		if depth > 0 && r.listKind[depth-1] == atom.Ol {
+			r.listNum[depth-1]++
+			marker = strconv.Itoa(r.listNum[depth-1]) + ". "
+		}
+		if depth > 1 {
+			r.current.WriteString(strings.Repeat("  ", depth-1))
+		}
+		r.current.WriteString(marker)
+		r.html.WriteString("<li>")
+		r.renderChildren(n)
+		r.html.WriteString("</li>")
+		r.endLine()
+
+	case n.DataAtom == atom.Br:
+		r.endLine()
+		r.html.WriteString("<br>")
+
+	case n.DataAtom == atom.A:
+		href := attr(n, "href")
+		if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") || strings.HasPrefix(href, "mailto:") {
+			r.html.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener" target="_blank">`)
+			r.renderChildren(n)
+			r.html.WriteString("</a>")
+		} else {
+			r.renderChildren(n)
+		}
+
+	case inlineKeepTags[n.DataAtom] != "":
+		tag := inlineKeepTags[n.DataAtom]
+		r.html.WriteString("<" + tag + ">")
+		r.renderChildren(n)
+		r.html.WriteString("</" + tag + ">")
+
+	case blockTags[n.DataAtom]:
+		r.endLine()
+		if n.DataAtom == atom.P {
+			r.html.WriteString("<p>")
+			r.renderChildren(n)
+			r.html.WriteString("</p>")
+		} else {
+			r.renderChildren(n)
+		}
+		r.endBlock()
+
+	default:
+		r.renderChildren(n)
+	}
+}
+
+func (r *contentRenderer) renderChildren(n *xhtml.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.render(c)
+	}
+}
+
+// endLine finishes the current line of text, if any
+func (r *contentRenderer) endLine() {
+	line := strings.TrimRight(r.current.String(), " ")
+	r.current.Reset()
+	if strings.TrimSpace(line) == "" || line == "- " {
+		return
+	}
+	r.lines = append(r.lines, line)
+}
+
+// endBlock finishes the line and leaves a blank line before the next block
+func (r *contentRenderer) endBlock() {
+	r.endLine()
+	if len(r.lines) > 0 && r.lines[len(r.lines)-1] != "" {
+		r.lines = append(r.lines, "")
+	}
+}
+
+func (r *contentRenderer) text() string {
+	r.endLine()
+	return strings.TrimSpace(strings.Join(r.lines, "\n"))
+}
+
+func headingOpen(level int) string  { return "<h" + strconv.Itoa(level) + ">" }
+func headingClose(level int) string { return "</h" + strconv.Itoa(level) + ">" }
+
+func findFirst(n *xhtml.Node, a atom.Atom) *xhtml.Node {
+	if n.Type == xhtml.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirst(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func innerText(n *xhtml.Node) string {
+	var b strings.Builder
+	var walk func(*xhtml.Node)
+	walk = func(c *xhtml.Node) {
+		if c.Type == xhtml.TextNode {
+			b.WriteString(c.Data)
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.TrimSpace(spaceRun.ReplaceAllString(b.String(), " "))
+}
+
+func attr(n *xhtml.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	page, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func TestExtractMainContentRemovesBoilerplate(t *testing.T) {
+	content := ExtractMainContent(readFixture(t, "job_page.html"))
+
+	for _, want := range []string{
+		"# SOC Analyst",
+		"## Responsibilities",
+		"- Triage SIEM alerts in Splunk and escalate confirmed incidents.",
+		"### Requirements",
+		"1. 2+ years in a SOC, NOC or incident response role.",
+		"2. Working knowledge of TCP/IP",
+	} {
+		if !strings.Contains(content.Text, want) {
+			t.Errorf("text is missing %q:\n%s", want, content.Text)
+		}
+	}
+	for _, boilerplate := range []string{"cookies", "Login", "Similar jobs", "newsletter", "All rights reserved", "dataLayer", "font-family"} {
+		if strings.Contains(content.Text, boilerplate) || strings.Contains(content.HTML, boilerplate) {
+			t.Errorf("boilerplate %q was kept:\n%s", boilerplate, content.Text)
+		}
+	}
+}
+
+func TestExtractMainContentSanitizesLinks(t *testing.T) {
+	content := ExtractMainContent(readFixture(t, "job_page.html"))
+
+	for _, want := range []string{
+		`<a href="https://acme.example/careers/benefits" rel="nofollow noopener" target="_blank">benefits guide</a>`,
+		`<a href="mailto:jobs@acme.example" rel="nofollow noopener" target="_blank">jobs@acme.example</a>`,
+		"<strong>SIEM</strong>",
+	} {
+		if !strings.Contains(content.HTML, want) {
+			t.Errorf("HTML is missing %s:\n%s", want, content.HTML)
+		}
+	}
+	// Script and relative links keep their text but lose the link
+	for _, unsafe := range []string{"javascript:", `href="/privacy"`, "onclick", "<img", "<iframe", "<script", "class="} {
+		if strings.Contains(content.HTML, unsafe) {
+			t.Errorf("HTML contains %s:\n%s", unsafe, content.HTML)
+		}
+	}
+	if !strings.Contains(content.HTML, "interactive tour") || !strings.Contains(content.HTML, "privacy notice") {
+		t.Errorf("link text was dropped:\n%s", content.HTML)
+	}
+}
+
+func TestExtractMainContentBreaksTiesInDocumentOrder(t *testing.T) {
+	paragraph := "<p>Monitor alerts, triage incidents, tune detections and write reports for the security team every week.</p>"
+	page := []byte(`<html><body>` +
+		`<section><h2>First</h2>` + paragraph + paragraph + `</section>` +
+		`<section><h2>Second</h2>` + paragraph + paragraph + `</section>` +
+		`</body></html>`)
+
+	for i := 0; i < 20; i++ {
+		content := ExtractMainContent(page)
+		if !strings.HasPrefix(content.Text, "## First") || strings.Contains(content.Text, "Second") {
+			t.Fatalf("run %d picked the wrong section:\n%s", i, content.Text)
+		}
+	}
+}
+
+func TestExtractMainContentFallsBackToBody(t *testing.T) {
+	content := ExtractMainContent([]byte(`<html><body><nav>Menu</nav><p>Short ad.</p><p>Apply by Friday.</p></body></html>`))
+	if content.Text != "Short ad.\n\nApply by Friday." {
+		t.Errorf("text = %q", content.Text)
+	}
+}
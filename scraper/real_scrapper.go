@@ -1,17 +1,26 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/C9b3rD3vi1/jobhunter-tool/certifications"
 	"github.com/C9b3rD3vi1/jobhunter-tool/database"
+	"github.com/C9b3rD3vi1/jobhunter-tool/embeddings"
+	"github.com/C9b3rD3vi1/jobhunter-tool/feedback"
 	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	userprofile "github.com/C9b3rD3vi1/jobhunter-tool/profile"
+	"github.com/C9b3rD3vi1/jobhunter-tool/relevance"
+	"github.com/C9b3rD3vi1/jobhunter-tool/sections"
 	"github.com/gocolly/colly/v2"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/datatypes"
 )
 
@@ -20,6 +29,22 @@ type RealScraper struct {
 	db        *database.DB
 	mu        sync.Mutex
 	jobsFound int
+
+	rescoreMu      sync.Mutex
+	rescore        RescoreStatus
+	rescorePending string // reason for a run queued behind the current one
+
+	relevanceMu sync.Mutex
+	relevance   *relevance.Index // built on first use
+
+	feedbackMu      sync.Mutex
+	feedback        *feedback.Model // nil until enough jobs are rated
+	feedbackTrained bool
+
+	semanticMu     sync.Mutex
+	embedder       embeddings.Provider
+	semantic       semanticProfile
+	semanticFlight singleflight.Group // keyed by profile hash
 }
 
 // ScrapingConfig holds configuration for scraping behavior
@@ -84,6 +109,7 @@ func NewRealScraperWithConfig(db *database.DB, config ScrapingConfig) *RealScrap
 	return &RealScraper{
 		collector: c,
 		db:        db,
+		embedder:  embeddings.NewHashProvider(embeddings.DefaultHashDimensions),
 	}
 }
 
@@ -255,19 +281,20 @@ func (s *RealScraper) extractFuzuJob(e *colly.HTMLElement) *models.Job {
 
 func (s *RealScraper) ScrapeCompanyPages() error {
 	companies := []struct {
-		name string
-		url  string
+		name     string
+		url      string
+		location string // headquarters, used when a listing has no location
 	}{
-		{"Safaricom", "https://www.safaricom.co.ke/careers/"},
-		{"KCB Bank", "https://www.kcbgroup.com/careers/"},
-		{"Equity Bank", "https://www.equitybankgroup.com/careers/"},
+		{"Safaricom", "https://www.safaricom.co.ke/careers/", "Nairobi, Kenya"},
+		{"KCB Bank", "https://www.kcbgroup.com/careers/", "Nairobi, Kenya"},
+		{"Equity Bank", "https://www.equitybankgroup.com/careers/", "Nairobi, Kenya"},
 	}
 
 	var jobsFound int
 
 	s.collector.OnHTML("div.job-listing, li.job, tr.job-row, a[href*='job'], div[class*='job']", func(e *colly.HTMLElement) {
 		for _, company := range companies {
-			job := s.extractCompanyJob(e, company.name, company.url)
+			job := s.extractCompanyJob(e, company.name, company.url, company.location)
 			if job != nil {
 				s.enrichAndSaveJob(job)
 				jobsFound++
@@ -288,7 +315,7 @@ func (s *RealScraper) ScrapeCompanyPages() error {
 	return nil
 }
 
-func (s *RealScraper) extractCompanyJob(e *colly.HTMLElement, companyName, companyURL string) *models.Job {
+func (s *RealScraper) extractCompanyJob(e *colly.HTMLElement, companyName, companyURL, defaultLocation string) *models.Job {
 	title := strings.TrimSpace(e.ChildText("h3, h4, .title, .job-title"))
 	if title == "" {
 		title = strings.TrimSpace(e.Text)
@@ -304,11 +331,16 @@ func (s *RealScraper) extractCompanyJob(e *colly.HTMLElement, companyName, compa
 		jobURL = companyURL + jobURL
 	}
 
+	location := strings.TrimSpace(e.ChildText("[class*='location'], [class*='address']"))
+	if location == "" {
+		location = defaultLocation
+	}
+
 	return &models.Job{
 		ID:         fmt.Sprintf("comp-%d", time.Now().UnixNano()),
 		Title:      title,
 		Company:    companyName,
-		Location:   "Nairobi, Kenya",
+		Location:   location,
 		Source:     "Company Website",
 		URL:        jobURL,
 		PostedDate: time.Now().Format("2006-01-02"),
@@ -337,21 +369,61 @@ func (s *RealScraper) isRelevantJob(title string) bool {
 }
 
 func (s *RealScraper) enrichAndSaveJob(job *models.Job) {
-	// Get full description if URL is available
+	// Get full description and structured data if URL is available
+	var details JobDetails
 	if job.URL != "" {
-		job.Description = s.ScrapeJobDescription(job.URL)
+		details = s.ScrapeJobDetails(job.URL)
+		if details.Description != "" {
+			job.Description = details.Description
+			job.DescriptionHTML = details.DescriptionHTML
+		}
 	}
 
+	// Structured address from the detail page is more reliable than listing text
+	if details.Posting != nil && details.Posting.Location() != "" {
+		job.Location = details.Posting.Location()
+	}
+	s.NormalizeJobLocation(job)
+
 	// Extract and set job attributes
+	job.Sections = s.ConvertSectionsToJSON(sections.Parse(job.Description))
 	job.Skills = s.ConvertToJSON(s.ExtractSkills(job.Description + " " + job.Title))
 	job.TechStack = s.ConvertToJSON(s.ExtractTechStack(job.Description))
-	job.Score = s.CalculateScore(job)
+	job.Certifications = s.ConvertToJSON(certifications.Extract(job.Title + " " + job.Description))
 	job.SalaryRange = s.ExtractSalary(job.Description)
 	job.Experience = s.ExtractExperience(job.Description)
+	job.EmploymentType = s.ExtractEmploymentType(job.Title+" "+job.Description, details.Posting)
+	job.WorkMode = s.ExtractWorkMode(job.Description, job.Location, details.Posting)
+	job.ApplicationDeadline = s.ExtractDeadline(job.Description, details.Posting)
+
+	// The real posting date, when the page states one, for freshness ranking
+	if details.Posting != nil {
+		if posted := parseDeadline(details.Posting.DatePosted); posted != "" {
+			job.PostedDate = posted
+		}
+	}
+
+	// Score last so location and work mode preferences can be applied
+	breakdown := s.ScoreJob(context.Background(), job)
+	rules, err := s.db.GetExclusionRules()
+	if err != nil {
+		log.Printf("⚠️ Error loading exclusion rules: %v", err)
+	}
+	s.ApplyExclusionRules(job, &breakdown, CompileExclusionRules(rules))
+	job.Score = breakdown.Total
+	job.ScoreBreakdown = s.ConvertBreakdownToJSON(breakdown)
+
+	// Embedded now so the job page can find similar jobs from stored vectors
+	if _, err := s.EmbedJobs(context.Background(), []models.Job{*job}); err != nil {
+		log.Printf("⚠️ Error embedding job '%s': %v", job.Title, err)
+	}
 
-	// Save to database
+	// Save to database. Dropped jobs are kept so the filtered view can show
+	// what was excluded and why.
 	if err := s.db.SaveJob(job); err != nil {
 		log.Printf("❌ Error saving job '%s' at '%s': %v", job.Title, job.Company, err)
+	} else if job.FilterAction == FilterDropped {
+		log.Printf("🚫 Dropped: %s at %s (%s)", job.Title, job.Company, strings.Join(parseStringList(job.FilterReasons), "; "))
 	} else {
 		s.mu.Lock()
 		s.jobsFound++
@@ -360,37 +432,38 @@ func (s *RealScraper) enrichAndSaveJob(job *models.Job) {
 	}
 }
 
+// JobDetails holds everything scraped from a job's detail page
+type JobDetails struct {
+	Description     string // structured plain text
+	DescriptionHTML string // sanitized HTML of the same content
+	Posting         *JobPostingData
+}
+
 func (s *RealScraper) ScrapeJobDescription(url string) string {
+	return s.ScrapeJobDetails(url).Description
+}
+
+func (s *RealScraper) ScrapeJobDetails(url string) JobDetails {
+	var details JobDetails
 	if url == "" {
-		return ""
+		return details
 	}
 
-	var description string
 	descCollector := colly.NewCollector()
 	descCollector.SetRequestTimeout(30 * time.Second)
 
-	// Try multiple selectors for job description
-	selectors := []string{
-		"div.job-description",
-		"div.description",
-		"article.content",
-		"section.description",
-		"div[class*='desc']",
-		"div.job-details",
-		"div.requirements",
-		"div.responsibilities",
-	}
-
-	descCollector.OnHTML(strings.Join(selectors, ", "), func(e *colly.HTMLElement) {
-		if description == "" {
-			description = strings.TrimSpace(e.Text)
-		}
+	// Main content is picked readability-style from the raw page so
+	// navigation, cookie banners and footers never end up in the description
+	descCollector.OnResponse(func(r *colly.Response) {
+		content := ExtractMainContent(r.Body)
+		details.Description = content.Text
+		details.DescriptionHTML = content.HTML
 	})
 
-	// Fallback to body text if no specific description found
-	descCollector.OnHTML("body", func(e *colly.HTMLElement) {
-		if description == "" {
-			description = strings.TrimSpace(e.Text)
+	// Structured JobPosting data, when the site provides it
+	descCollector.OnHTML(`script[type="application/ld+json"]`, func(e *colly.HTMLElement) {
+		if details.Posting == nil {
+			details.Posting = parseJobPostingJSONLD(e.Text)
 		}
 	})
 
@@ -398,7 +471,7 @@ func (s *RealScraper) ScrapeJobDescription(url string) string {
 		log.Printf("⚠️ Error scraping job description from %s: %v", url, err)
 	}
 
-	return description
+	return details
 }
 
 // SkillPattern defines a pattern for skill extraction
@@ -573,12 +646,23 @@ func (s *RealScraper) ExtractExperience(text string) string {
 }
 
 func (s *RealScraper) CalculateScore(job *models.Job) int {
-	if job == nil {
-		return 0
+	return s.ScoreJob(context.Background(), job).Total
+}
+
+// ScoreJob scores a job out of 100 using the saved scoring profile and
+// records how each component was earned. ctx bounds the embeddings calls.
+func (s *RealScraper) ScoreJob(ctx context.Context, job *models.Job) models.ScoreBreakdown {
+	profile, err := s.db.GetScoringProfile()
+	if err != nil {
+		log.Printf("⚠️ Error loading scoring profile, using defaults: %v", err)
+		profile = database.DefaultScoringProfile()
 	}
 
-	score := 0
-	text := strings.ToLower(job.Description + " " + job.Title)
+	user, err := s.db.GetUserProfile()
+	if err != nil {
+		log.Printf("⚠️ Error loading user profile: %v", err)
+		user = database.DefaultUserProfile()
+	}
 
 	// Get user skills from database
 	userSkills, err := s.db.GetUserSkills()
@@ -586,56 +670,186 @@ func (s *RealScraper) CalculateScore(job *models.Job) int {
 		userSkills = []string{"AWS", "Python", "Go", "Fortinet", "SIEM", "Docker"}
 	}
 
-	// Skill matching (60 points)
-	matchedSkills := 0
+	return s.ScoreJobWithProfile(ctx, job, userSkills, profile, user, s.latestResumeText())
+}
+
+// latestResumeText returns the text of the most recently uploaded resume, or
+// an empty string if there is none
+func (s *RealScraper) latestResumeText() string {
+	latest, err := s.db.GetLatestResume()
+	if err != nil {
+		log.Printf("⚠️ Error loading latest resume: %v", err)
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Text
+}
+
+// ScoreJobWithProfile scores a job against the given skills, scoring
+// settings, user profile and resume text. Each component earns up to its
+// weight in points; the total is scaled to 100 so weights need not add up
+// to 100.
+func (s *RealScraper) ScoreJobWithProfile(ctx context.Context, job *models.Job, userSkills []string, profile *models.ScoringProfile, user *models.UserProfile, resumeText string) models.ScoreBreakdown {
+	breakdown := models.ScoreBreakdown{
+		Components:      []models.ScoreComponent{},
+		MatchedSkills:   []models.SkillMatch{},
+		UnmatchedSkills: []string{},
+	}
+	if job == nil {
+		return breakdown
+	}
+
+	// Skill matching. A skill listed under requirements counts fully, one
+	// only mentioned as nice-to-have counts for less.
+	parsed := sections.Parse(job.Description)
+	matchedWeight := 0.0
 	for _, userSkill := range userSkills {
-		if strings.Contains(text, strings.ToLower(userSkill)) {
-			matchedSkills++
+		if sections.Mentions(job.Title, userSkill) {
+			matchedWeight++
+			breakdown.MatchedSkills = append(breakdown.MatchedSkills, models.SkillMatch{Skill: userSkill, Weight: 1, Where: "title"})
+			continue
+		}
+
+		kind, weight := sections.BestMatch(parsed, userSkill)
+		if weight == 0 {
+			breakdown.UnmatchedSkills = append(breakdown.UnmatchedSkills, userSkill)
+			continue
 		}
+		matchedWeight += weight
+		breakdown.MatchedSkills = append(breakdown.MatchedSkills, models.SkillMatch{Skill: userSkill, Weight: weight, Where: kind})
 	}
 
-	if len(userSkills) > 0 {
-		skillRatio := float64(matchedSkills) / float64(len(userSkills))
-		score += int(skillRatio * 60)
+	if profile.SkillsWeight > 0 {
+		skillRatio := 0.0
+		if len(userSkills) > 0 {
+			skillRatio = matchedWeight / float64(len(userSkills))
+		}
+		breakdown.Components = append(breakdown.Components, models.ScoreComponent{
+			Name:      "Skills",
+			Points:    int(skillRatio * float64(profile.SkillsWeight)),
+			MaxPoints: profile.SkillsWeight,
+			Rule:      fmt.Sprintf("%d of %d profile skills found (weighted %.1f)", len(breakdown.MatchedSkills), len(userSkills), matchedWeight),
+		})
 	}
 
-	// Experience level matching (20 points)
-	score += s.calculateExperienceScore(text)
+	if profile.ExperienceWeight > 0 {
+		fraction, rule := s.calculateExperienceScore(job, profile, user)
+		breakdown.Components = append(breakdown.Components, weightedComponent("Experience", profile.ExperienceWeight, fraction, rule))
+	}
 
-	// Salary indication (10 points)
-	if strings.Contains(text, "ksh") || strings.Contains(text, "salary") || strings.Contains(text, "compensation") {
-		score += 10
+	if profile.SalaryWeight > 0 {
+		fraction, rule := s.calculateSalaryScore(job.Description, user.SalaryMin)
+		breakdown.Components = append(breakdown.Components, weightedComponent("Salary", profile.SalaryWeight, fraction, rule))
 	}
 
-	// Company reputation (10 points)
-	score += s.calculateCompanyScore(job.Company)
+	if profile.CompanyWeight > 0 {
+		fraction, rule := s.calculateCompanyScore(job.Company, parseStringList(profile.PreferredCompanies))
+		breakdown.Components = append(breakdown.Components, weightedComponent("Company", profile.CompanyWeight, fraction, rule))
+	}
+
+	// Relevance only counts once the user has described themselves, listed
+	// skills or uploaded a resume
+	profileText := userprofile.RankingText(user, userSkills, resumeText)
+	if profile.RelevanceWeight > 0 && profileText != "" {
+		fraction, rule := s.calculateRelevanceScore(job, profileText)
+		breakdown.Components = append(breakdown.Components, weightedComponent("Relevance", profile.RelevanceWeight, fraction, rule))
+	}
+	if profile.SemanticWeight > 0 && profileText != "" {
+		if fraction, rule, ok := s.calculateSemanticScore(ctx, job, profileText); ok {
+			breakdown.Components = append(breakdown.Components, weightedComponent("Semantic", profile.SemanticWeight, fraction, rule))
+		}
+	}
+
+	if profile.FeedbackWeight > 0 {
+		if fraction, rule, ok := s.calculateFeedbackScore(job); ok {
+			breakdown.Components = append(breakdown.Components, weightedComponent("Feedback", profile.FeedbackWeight, fraction, rule))
+		}
+	}
+
+	// Location only counts once the user has said where they want to work
+	if preferred := userprofile.TargetLocations(user); profile.LocationWeight > 0 && len(preferred) > 0 {
+		fraction, rule := s.calculateLocationScore(job, preferred)
+		breakdown.Components = append(breakdown.Components, weightedComponent("Location", profile.LocationWeight, fraction, rule))
+	}
+
+	points, maxPoints := 0, 0
+	for _, component := range breakdown.Components {
+		points += component.Points
+		maxPoints += component.MaxPoints
+	}
+	if maxPoints > 0 {
+		breakdown.Total = min(points*100/maxPoints, 100)
+	}
+	return breakdown
+}
+
+func weightedComponent(name string, weight int, fraction float64, rule string) models.ScoreComponent {
+	return models.ScoreComponent{Name: name, Points: int(fraction * float64(weight)), MaxPoints: weight, Rule: rule}
+}
+
+var salaryAmount = regexp.MustCompile(`\d[\d,]*`)
+
+// calculateSalaryScore rewards postings that mention pay; with a minimum
+// salary set, a stated salary below it earns nothing.
+func (s *RealScraper) calculateSalaryScore(description string, minSalary int) (float64, string) {
+	if salary := s.ExtractSalary(description); salary != "Negotiable" {
+		amount := statedSalaryAmount(salary)
+		if minSalary > 0 && amount > 0 && amount < minSalary {
+			return 0, fmt.Sprintf("%s is below the KSh %d minimum", salary, minSalary)
+		}
+		return 1, fmt.Sprintf("states salary %s", salary)
+	}
 
-	return min(score, 100)
+	text := strings.ToLower(description)
+	for _, keyword := range []string{"ksh", "salary", "compensation"} {
+		if strings.Contains(text, keyword) {
+			return 1, fmt.Sprintf("mentions %q", keyword)
+		}
+	}
+	return 0, "no salary information"
 }
 
-func (s *RealScraper) calculateExperienceScore(text string) int {
-	switch {
-	case strings.Contains(text, "5 years") || strings.Contains(text, "senior") || strings.Contains(text, "lead"):
-		return 20
-	case strings.Contains(text, "3 years") || strings.Contains(text, "mid-level") || strings.Contains(text, "intermediate"):
-		return 15
-	case strings.Contains(text, "1 year") || strings.Contains(text, "junior") || strings.Contains(text, "entry"):
-		return 10
-	default:
-		return 5
+// statedSalaryAmount returns the highest figure in an extracted salary
+// range, or 0 when there is none
+func statedSalaryAmount(salary string) int {
+	amount := 0
+	for _, match := range salaryAmount.FindAllString(salary, -1) {
+		if n, err := strconv.Atoi(strings.ReplaceAll(match, ",", "")); err == nil && n > amount {
+			amount = n
+		}
 	}
+	return amount
 }
 
-func (s *RealScraper) calculateCompanyScore(company string) int {
-	premiumCompanies := []string{"safaricom", "kcb", "equity", "google", "microsoft", "amazon", "oracle", "ibm"}
+func (s *RealScraper) calculateCompanyScore(company string, preferred []string) (float64, string) {
 	companyLower := strings.ToLower(company)
 
-	for _, premium := range premiumCompanies {
-		if strings.Contains(companyLower, premium) {
-			return 10
+	for _, name := range preferred {
+		if name != "" && strings.Contains(companyLower, strings.ToLower(name)) {
+			return 1, fmt.Sprintf("preferred employer (%s)", name)
+		}
+	}
+	return 0.5, "not a preferred employer"
+}
+
+func (s *RealScraper) calculateLocationScore(job *models.Job, preferred []string) (float64, string) {
+	fields := strings.ToLower(strings.Join([]string{job.Location, job.City, job.Region, job.Country, job.WorkMode}, " "))
+
+	for _, place := range preferred {
+		if place != "" && strings.Contains(fields, strings.ToLower(place)) {
+			return 1, fmt.Sprintf("in preferred location (%s)", place)
 		}
 	}
-	return 5
+	return 0, "outside preferred locations"
+}
+
+func parseStringList(data []byte) []string {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil
+	}
+	return list
 }
 
 func (s *RealScraper) ConvertToJSON(slice []string) datatypes.JSON {
@@ -652,6 +866,30 @@ func (s *RealScraper) ConvertToJSON(slice []string) datatypes.JSON {
 	return datatypes.JSON(jsonData)
 }
 
+func (s *RealScraper) ConvertBreakdownToJSON(breakdown models.ScoreBreakdown) datatypes.JSON {
+	jsonData, err := json.Marshal(breakdown)
+	if err != nil {
+		log.Printf("❌ Error marshaling score breakdown: %v", err)
+		return datatypes.JSON([]byte(`{}`))
+	}
+
+	return datatypes.JSON(jsonData)
+}
+
+func (s *RealScraper) ConvertSectionsToJSON(parsed []sections.Section) datatypes.JSON {
+	if len(parsed) == 0 {
+		return datatypes.JSON([]byte(`[]`))
+	}
+
+	jsonData, err := json.Marshal(parsed)
+	if err != nil {
+		log.Printf("❌ Error marshaling sections: %v", err)
+		return datatypes.JSON([]byte(`[]`))
+	}
+
+	return datatypes.JSON(jsonData)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
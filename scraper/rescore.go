@@ -0,0 +1,161 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/database"
+)
+
+// rescoreBatchSize is how many jobs are loaded from the database at a time
+const rescoreBatchSize = 200
+
+// RescoreStatus reports the progress of the latest re-scoring run
+type RescoreStatus struct {
+	Running    bool      `json:"running"`
+	Reason     string    `json:"reason"`
+	Total      int       `json:"total"`
+	Done       int       `json:"done"`
+	Changed    int       `json:"changed"` // jobs whose score changed
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// StartRescore re-scores every stored job in the background. If a run is
+// already in progress another one is queued, so changes made mid-run are
+// still picked up; it returns false in that case.
+func (s *RealScraper) StartRescore(reason string) bool {
+	s.rescoreMu.Lock()
+	defer s.rescoreMu.Unlock()
+
+	if s.rescore.Running {
+		s.rescorePending = reason
+		return false
+	}
+
+	s.rescore = RescoreStatus{Running: true, Reason: reason, StartedAt: time.Now()}
+	go s.runRescores()
+	return true
+}
+
+// RescoreProgress returns a snapshot of the current or last re-scoring run
+func (s *RealScraper) RescoreProgress() RescoreStatus {
+	s.rescoreMu.Lock()
+	defer s.rescoreMu.Unlock()
+	return s.rescore
+}
+
+func (s *RealScraper) runRescores() {
+	for {
+		log.Printf("🔄 Re-scoring all jobs (%s)...", s.RescoreProgress().Reason)
+		err := s.RescoreAll(func(done, total, changed int) {
+			s.rescoreMu.Lock()
+			s.rescore.Done, s.rescore.Total, s.rescore.Changed = done, total, changed
+			s.rescoreMu.Unlock()
+		})
+
+		s.rescoreMu.Lock()
+		s.rescore.FinishedAt = time.Now()
+		if err != nil {
+			s.rescore.Error = err.Error()
+			log.Printf("❌ Re-scoring failed: %v", err)
+		} else {
+			log.Printf("✅ Re-scored %d jobs, %d changed", s.rescore.Done, s.rescore.Changed)
+		}
+
+		if s.rescorePending == "" {
+			s.rescore.Running = false
+			s.rescoreMu.Unlock()
+			return
+		}
+		s.rescore = RescoreStatus{Running: true, Reason: s.rescorePending, StartedAt: time.Now()}
+		s.rescorePending = ""
+		s.rescoreMu.Unlock()
+	}
+}
+
+// RescoreAll recomputes the score, breakdown and exclusion result of every
+// stored job from its saved description, using the current skills, scoring
+// settings, user profile, latest resume and exclusion rules. progress is
+// called after each batch.
+func (s *RealScraper) RescoreAll(progress func(done, total, changed int)) error {
+	profile, err := s.db.GetScoringProfile()
+	if err != nil {
+		log.Printf("⚠️ Error loading scoring profile, using defaults: %v", err)
+		profile = database.DefaultScoringProfile()
+	}
+
+	user, err := s.db.GetUserProfile()
+	if err != nil {
+		return err
+	}
+
+	userSkills, err := s.db.GetUserSkills()
+	if err != nil {
+		return err
+	}
+	resumeText := s.latestResumeText()
+
+	savedRules, err := s.db.GetExclusionRules()
+	if err != nil {
+		return err
+	}
+	rules := CompileExclusionRules(savedRules)
+
+	// Fresh corpus statistics, so every job is ranked against the same index
+	if err := s.RebuildRelevanceIndex(); err != nil {
+		return err
+	}
+	if err := s.RetrainFeedbackModel(); err != nil {
+		return err
+	}
+	// Jobs without a vector for the current model are embedded first, so
+	// the best semantic match and similar jobs can use stored vectors
+	if err := s.embedAllJobs(context.Background()); err != nil {
+		log.Printf("⚠️ Error embedding jobs: %v", err)
+	}
+	s.resetSemantic()
+
+	total, err := s.db.CountJobs()
+	if err != nil {
+		return err
+	}
+
+	done, changed := 0, 0
+	lastID := ""
+	for {
+		jobs, err := s.db.GetJobsAfter(lastID, rescoreBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		for i := range jobs {
+			job := &jobs[i]
+			previousScore, previousAction := job.Score, job.FilterAction
+
+			breakdown := s.ScoreJobWithProfile(context.Background(), job, userSkills, profile, user, resumeText)
+			s.ApplyExclusionRules(job, &breakdown, rules)
+			job.Score = breakdown.Total
+			job.ScoreBreakdown = s.ConvertBreakdownToJSON(breakdown)
+
+			if err := s.db.UpdateJobScore(job); err != nil {
+				return err
+			}
+			if job.Score != previousScore || job.FilterAction != previousAction {
+				changed++
+			}
+			done++
+		}
+
+		lastID = jobs[len(jobs)-1].ID
+		if progress != nil {
+			// Jobs scraped mid-run can push done past the initial count
+			progress(done, max(total, done), changed)
+		}
+	}
+}
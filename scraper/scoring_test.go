@@ -0,0 +1,42 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/relevance"
+)
+
+func TestScoreJobMatchesSkillsAsWholeWords(t *testing.T) {
+	job := &models.Job{
+		Title:       "Google Workspace Associate",
+		Description: "Support our Google Workspace tenant as an associate administrator. Linux exposure helps.",
+	}
+	profile := &models.ScoringProfile{SkillsWeight: 100}
+
+	breakdown := (&RealScraper{}).ScoreJobWithProfile(context.Background(), job, []string{"Go", "SOC", "Linux"}, profile, &models.UserProfile{}, "")
+
+	if len(breakdown.MatchedSkills) != 1 || breakdown.MatchedSkills[0].Skill != "Linux" {
+		t.Errorf("MatchedSkills = %+v, want only Linux", breakdown.MatchedSkills)
+	}
+	if len(breakdown.UnmatchedSkills) != 2 {
+		t.Errorf("UnmatchedSkills = %v, want Go and SOC", breakdown.UnmatchedSkills)
+	}
+}
+
+func TestScoreJobRanksAgainstResume(t *testing.T) {
+	s := &RealScraper{relevance: relevance.NewIndex()}
+	s.relevance.Add("other", "Accountant preparing monthly payroll in Excel")
+	job := &models.Job{URL: "https://jobs.example/1", Title: "SOC Analyst", Description: "Triage Splunk alerts and tune SIEM rules"}
+	profile := &models.ScoringProfile{RelevanceWeight: 20}
+
+	if breakdown := s.ScoreJobWithProfile(context.Background(), job, nil, profile, &models.UserProfile{}, ""); len(breakdown.Components) != 0 {
+		t.Errorf("blank profile scored relevance: %+v", breakdown.Components)
+	}
+
+	breakdown := s.ScoreJobWithProfile(context.Background(), job, nil, profile, &models.UserProfile{}, "Three years triaging Splunk and SIEM alerts")
+	if len(breakdown.Components) != 1 || breakdown.Components[0].Name != "Relevance" || breakdown.Components[0].Points != 20 {
+		t.Errorf("components = %+v, want full relevance from the resume", breakdown.Components)
+	}
+}
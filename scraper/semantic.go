@@ -0,0 +1,257 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/embeddings"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+const (
+	// embedBatchSize is how many texts are sent to the provider at once
+	embedBatchSize = 64
+	embedTimeout   = 60 * time.Second
+)
+
+// semanticProfile caches the profile vector and the best similarity any
+// stored job has to it
+type semanticProfile struct {
+	hash   string // content hash of the profile text; "" when not computed
+	vector []float32
+	best   float64
+}
+
+// SetEmbedder replaces the embeddings provider. Vectors are cached per
+// model, so switching providers re-embeds jobs as they are scored.
+func (s *RealScraper) SetEmbedder(provider embeddings.Provider) {
+	s.semanticMu.Lock()
+	defer s.semanticMu.Unlock()
+	s.embedder = provider
+	s.semantic = semanticProfile{}
+}
+
+// Embedder returns the embeddings provider in use
+func (s *RealScraper) Embedder() embeddings.Provider {
+	s.semanticMu.Lock()
+	defer s.semanticMu.Unlock()
+	return s.embedder
+}
+
+// EmbedTexts returns a vector per text, reusing stored vectors and saving
+// new ones. Each call to the provider is bounded by embedTimeout as well as
+// by ctx.
+func (s *RealScraper) EmbedTexts(ctx context.Context, texts []string) ([][]float32, error) {
+	provider := s.Embedder()
+	model := provider.Model()
+
+	prepared := make([]string, len(texts))
+	hashes := make([]string, len(texts))
+	for i, text := range texts {
+		prepared[i] = embeddings.PrepareInput(text)
+		hashes[i] = embeddings.ContentHash(model, prepared[i])
+	}
+
+	stored, err := s.db.GetEmbeddings(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(texts))
+	var missing []int
+	for i, hash := range hashes {
+		if data, ok := stored[hash]; ok {
+			vectors[i] = embeddings.Decode(data)
+		} else {
+			missing = append(missing, i)
+		}
+	}
+
+	for start := 0; start < len(missing); start += embedBatchSize {
+		batch := missing[start:min(start+embedBatchSize, len(missing))]
+		batchTexts := make([]string, len(batch))
+		for j, i := range batch {
+			batchTexts[j] = prepared[i]
+		}
+
+		batchCtx, cancel := context.WithTimeout(ctx, embedTimeout)
+		embedded, err := provider.Embed(batchCtx, batchTexts)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		records := make([]models.Embedding, len(batch))
+		for j, i := range batch {
+			vectors[i] = embedded[j]
+			records[j] = models.Embedding{Hash: hashes[i], Model: model, Vector: embeddings.Encode(embedded[j])}
+		}
+		if err := s.db.SaveEmbeddings(records); err != nil {
+			log.Printf("⚠️ Error saving embeddings: %v", err)
+		}
+	}
+	return vectors, nil
+}
+
+// EmbedJobs returns a vector per job, from its title and description
+func (s *RealScraper) EmbedJobs(ctx context.Context, jobs []models.Job) ([][]float32, error) {
+	texts := make([]string, len(jobs))
+	for i := range jobs {
+		texts[i] = relevanceText(&jobs[i])
+	}
+	return s.EmbedTexts(ctx, texts)
+}
+
+// embedAllJobs embeds every stored job that has no vector for the current
+// model yet. Only background work calls it: it can send the whole table to
+// the provider.
+func (s *RealScraper) embedAllJobs(ctx context.Context) error {
+	lastID := ""
+	for {
+		jobs, err := s.db.GetJobsAfter(lastID, rescoreBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+		if _, err := s.EmbedJobs(ctx, jobs); err != nil {
+			return err
+		}
+		lastID = jobs[len(jobs)-1].ID
+	}
+}
+
+// storedJobVectors returns each job's stored vector, or nil for jobs that
+// haven't been embedded with the current model. It never calls the provider.
+func (s *RealScraper) storedJobVectors(jobs []models.Job) ([][]float32, error) {
+	model := s.Embedder().Model()
+	hashes := make([]string, len(jobs))
+	for i := range jobs {
+		hashes[i] = embeddings.ContentHash(model, embeddings.PrepareInput(relevanceText(&jobs[i])))
+	}
+
+	stored, err := s.db.GetEmbeddings(hashes)
+	if err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(jobs))
+	for i, hash := range hashes {
+		if data, ok := stored[hash]; ok {
+			vectors[i] = embeddings.Decode(data)
+		}
+	}
+	return vectors, nil
+}
+
+// resetSemantic drops the cached profile vector, so the best similarity is
+// recomputed over the jobs now stored
+func (s *RealScraper) resetSemantic() {
+	s.semanticMu.Lock()
+	s.semantic = semanticProfile{}
+	s.semanticMu.Unlock()
+}
+
+// profileSemantics returns the profile vector and the best similarity of any
+// stored job to it. Only the profile text is sent to the provider; jobs are
+// compared using their stored vectors, so jobs not yet embedded are left out
+// until the background rescore embeds them. Concurrent callers with the same
+// profile share one computation, and each stops waiting when its ctx ends.
+func (s *RealScraper) profileSemantics(ctx context.Context, profileText string) (semanticProfile, error) {
+	hash := embeddings.ContentHash(s.Embedder().Model(), embeddings.PrepareInput(profileText))
+
+	s.semanticMu.Lock()
+	cached := s.semantic
+	s.semanticMu.Unlock()
+	if cached.hash == hash {
+		return cached, nil
+	}
+
+	// The shared computation must not fail because the first caller's
+	// request ended; EmbedTexts still bounds it with embedTimeout
+	shared := context.WithoutCancel(ctx)
+	result := s.semanticFlight.DoChan(hash, func() (interface{}, error) {
+		return s.computeProfileSemantics(shared, hash, profileText)
+	})
+	select {
+	case r := <-result:
+		if r.Err != nil {
+			return cached, r.Err
+		}
+		return r.Val.(semanticProfile), nil
+	case <-ctx.Done():
+		return cached, ctx.Err()
+	}
+}
+
+func (s *RealScraper) computeProfileSemantics(ctx context.Context, hash, profileText string) (semanticProfile, error) {
+	vectors, err := s.EmbedTexts(ctx, []string{profileText})
+	if err != nil {
+		return semanticProfile{}, err
+	}
+	result := semanticProfile{hash: hash, vector: vectors[0]}
+
+	lastID := ""
+	for {
+		jobs, err := s.db.GetJobsAfter(lastID, rescoreBatchSize)
+		if err != nil {
+			return semanticProfile{}, err
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		jobVectors, err := s.storedJobVectors(jobs)
+		if err != nil {
+			return semanticProfile{}, err
+		}
+		for _, vector := range jobVectors {
+			if vector != nil {
+				result.best = max(result.best, embeddings.Cosine(result.vector, vector))
+			}
+		}
+		lastID = jobs[len(jobs)-1].ID
+	}
+
+	s.semanticMu.Lock()
+	s.semantic = result
+	s.semanticMu.Unlock()
+	return result, nil
+}
+
+// calculateSemanticScore compares the job's embedding with the profile's.
+// Like relevance, it is relative to the best-matching stored job. It returns
+// false if the embeddings provider fails, so the component is left out
+// rather than scored as a mismatch.
+func (s *RealScraper) calculateSemanticScore(ctx context.Context, job *models.Job, profileText string) (float64, string, bool) {
+	profile, err := s.profileSemantics(ctx, profileText)
+	if err != nil {
+		log.Printf("⚠️ Error embedding profile: %v", err)
+		return 0, "", false
+	}
+
+	vectors, err := s.EmbedJobs(ctx, []models.Job{*job})
+	if err != nil {
+		log.Printf("⚠️ Error embedding job '%s': %v", job.Title, err)
+		return 0, "", false
+	}
+	similarity := embeddings.Cosine(profile.vector, vectors[0])
+
+	// A new job may match better than any stored one
+	if similarity > profile.best {
+		s.semanticMu.Lock()
+		if s.semantic.hash == profile.hash {
+			s.semantic.best = similarity
+		}
+		s.semanticMu.Unlock()
+		profile.best = similarity
+	}
+
+	if similarity <= 0 {
+		return 0, fmt.Sprintf("no semantic similarity to your profile (%s)", s.Embedder().Model()), true
+	}
+	fraction := minFloat(similarity/profile.best, 1)
+	return fraction, fmt.Sprintf("similarity %.2f to your profile, %.0f%% of the best-matching job (%s)",
+		similarity, fraction*100, s.Embedder().Model()), true
+}
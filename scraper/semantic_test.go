@@ -0,0 +1,190 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/database"
+	"github.com/C9b3rD3vi1/jobhunter-tool/embeddings"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// newTestScraper returns a scraper backed by a fresh database in a
+// temporary directory
+func newTestScraper(t *testing.T) *RealScraper {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	db, err := database.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewRealScraper(db)
+}
+
+// countingEmbedder records every text sent to the hash embedder. With a
+// gate, each call waits for the gate to close or the context to end.
+type countingEmbedder struct {
+	*embeddings.HashProvider
+	gate     chan struct{}
+	mu       sync.Mutex
+	embedded []string
+}
+
+func (p *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	p.mu.Lock()
+	p.embedded = append(p.embedded, texts...)
+	p.mu.Unlock()
+	if p.gate != nil {
+		select {
+		case <-p.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return p.HashProvider.Embed(ctx, texts)
+}
+
+func (p *countingEmbedder) count(text string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, embedded := range p.embedded {
+		if embedded == text {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEmbedTextsReusesStoredVectors(t *testing.T) {
+	s := newTestScraper(t)
+	provider := &countingEmbedder{HashProvider: embeddings.NewHashProvider(32)}
+	s.SetEmbedder(provider)
+
+	first, err := s.EmbedTexts(context.Background(), []string{"SOC analyst", "Network engineer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Whitespace is flattened before hashing, so this is the same text
+	second, err := s.EmbedTexts(context.Background(), []string{"Network   engineer", "Cloud engineer", "SOC analyst"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"SOC analyst", "Network engineer", "Cloud engineer"}; !reflect.DeepEqual(provider.embedded, want) {
+		t.Errorf("sent %q to the provider, want %q", provider.embedded, want)
+	}
+	if !reflect.DeepEqual(second[0], first[1]) || !reflect.DeepEqual(second[2], first[0]) {
+		t.Error("stored vectors differ from the ones first returned")
+	}
+
+	// Vectors are stored per model, so a new model embeds again
+	s.SetEmbedder(embeddings.NewHashProvider(16))
+	third, err := s.EmbedTexts(context.Background(), []string{"SOC analyst"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(third[0]) != 16 {
+		t.Errorf("got a %d-dimension vector from the 16-dimension model", len(third[0]))
+	}
+}
+
+func saveTestJobs(t *testing.T, s *RealScraper, jobs ...models.Job) {
+	t.Helper()
+	for i := range jobs {
+		jobs[i].ID = fmt.Sprintf("job-%d", i)
+		jobs[i].URL = "https://jobs.example/" + jobs[i].ID
+		if err := s.db.SaveJob(&jobs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSemanticScoreUsesStoredJobVectors(t *testing.T) {
+	s := newTestScraper(t)
+	provider := &countingEmbedder{HashProvider: embeddings.NewHashProvider(64)}
+	s.SetEmbedder(provider)
+
+	saveTestJobs(t, s,
+		models.Job{Title: "SOC Analyst", Company: "Acme", Description: "Triage Splunk SIEM alerts"},
+		models.Job{Title: "Pastry Chef", Company: "Bakery", Description: "Bake bread and cakes"},
+	)
+	stored, _ := s.db.GetJobs(-1, 0)
+	embedded := stored[:1]
+	if _, err := s.EmbedJobs(context.Background(), embedded); err != nil {
+		t.Fatal(err)
+	}
+	provider.embedded = nil
+
+	job := &models.Job{URL: "https://jobs.example/new", Title: "Security Analyst", Description: "Monitor SIEM alerts"}
+	if _, _, ok := s.calculateSemanticScore(context.Background(), job, "SOC analyst with Splunk"); !ok {
+		t.Fatal("semantic score left out")
+	}
+
+	// Only the profile and the job being scored go to the provider; the
+	// stored job without a vector waits for the background rescore
+	want := []string{"SOC analyst with Splunk", embeddings.PrepareInput(relevanceText(job))}
+	if !reflect.DeepEqual(provider.embedded, want) {
+		t.Errorf("sent %q to the provider, want %q", provider.embedded, want)
+	}
+}
+
+func TestProfileSemanticsSharedBetweenCallers(t *testing.T) {
+	s := newTestScraper(t)
+	provider := &countingEmbedder{HashProvider: embeddings.NewHashProvider(64), gate: make(chan struct{})}
+	s.SetEmbedder(provider)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.profileSemantics(context.Background(), "SOC analyst with Splunk")
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(provider.gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := provider.count("SOC analyst with Splunk"); n != 1 {
+		t.Errorf("embedded the profile %d times, want once", n)
+	}
+}
+
+func TestProfileSemanticsStopsAtCallerDeadline(t *testing.T) {
+	s := newTestScraper(t)
+	provider := &countingEmbedder{HashProvider: embeddings.NewHashProvider(64), gate: make(chan struct{})}
+	s.SetEmbedder(provider)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.profileSemantics(ctx, "SOC analyst with Splunk"); err != context.DeadlineExceeded {
+		t.Errorf("error = %v, want the deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waited %s past the deadline", elapsed)
+	}
+
+	// The computation carries on for later callers
+	close(provider.gate)
+	if _, err := s.profileSemantics(context.Background(), "SOC analyst with Splunk"); err != nil {
+		t.Fatal(err)
+	}
+	if n := provider.count("SOC analyst with Splunk"); n != 1 {
+		t.Errorf("embedded the profile %d times, want once", n)
+	}
+}
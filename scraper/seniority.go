@@ -0,0 +1,196 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+)
+
+// Seniority levels, in increasing order
+const (
+	levelEntry   = 1
+	levelMid     = 2
+	levelSenior  = 3
+	levelManager = 4
+)
+
+var levelNames = map[int]string{
+	levelEntry:   "entry",
+	levelMid:     "mid",
+	levelSenior:  "senior",
+	levelManager: "manager",
+}
+
+// seniorityTargets maps the settings page's seniority target to a level
+var seniorityTargets = map[string]int{"junior": levelEntry, "mid": levelMid, "senior": levelSenior, "manager": levelManager}
+
+const (
+	// roleNoun ends a title such as "Lead Software Engineer". "Lead",
+	// "staff" and "associate" only give a level before one, so "Lead
+	// Generation Executive", "Staff Accountant" and "Sales Associate" don't.
+	roleNoun = `(?:[a-z/&-]+\s+){0,2}?(?:engineer|developer|programmer|analyst|architect|scientist|designer|consultant|researcher|administrator|auditor|tester)s?\b`
+	// numeralEnd follows a grade numeral at the end of the title, or before
+	// a location or note as in "Engineer II - Nairobi"
+	numeralEnd = `\s*(?:$|[-–(,|])`
+)
+
+var (
+	// Checked against the job title, most senior first ("Senior Manager" is a manager role)
+	titleLevels = []struct {
+		level   int
+		pattern *regexp.Regexp
+	}{
+		{levelManager, regexp.MustCompile(`\b(manager|head of|director|chief|ciso|cto|vp|vice president)\b`)},
+		{levelSenior, regexp.MustCompile(`\b(senior|sr\.?|principal|architect)\b|\b(lead|staff)\s+` + roleNoun + `|\b(team|tech|technical) lead\b|\b(iii|iv)` + numeralEnd)},
+		{levelMid, regexp.MustCompile(`\b(mid[- ]level|intermediate)\b|\bii` + numeralEnd)},
+		{levelEntry, regexp.MustCompile(`\b(junior|jr\.?|entry[- ]level|graduate|intern|internship|trainee)\b|\bassociate\s+` + roleNoun + `|\bi` + numeralEnd)},
+	}
+
+	// "3+ years of experience", "3-5 years' hands-on experience", "minimum of 4 years"
+	yearsWithExperience = regexp.MustCompile(`\b(\d{1,2})\s*(?:\+|plus)?\s*(?:(?:-|–|to)\s*(\d{1,2})\s*)?\+?\s*years?(?:'|’)?\s+(?:of\s+)?(?:[a-z/&,-]+\s+){0,4}?experience`)
+	yearsMinimum        = regexp.MustCompile(`\b(?:minimum|at least|min\.?)\s+(?:of\s+)?(\d{1,2})\s*\+?\s*years?`)
+)
+
+// JobSeniority is what a posting says about the experience it wants
+type JobSeniority struct {
+	Level    int // 0 when the title gives no level
+	MinYears int // 0 when no years are stated
+	MaxYears int // 0 unless a range such as "3-5 years" is given
+}
+
+// ExtractSeniority reads the seniority level from the title and the years of
+// experience asked for in the description. When several year requirements
+// are listed, the largest is taken as the headline one.
+func ExtractSeniority(title, description string) JobSeniority {
+	var result JobSeniority
+
+	titleLower := strings.ToLower(title)
+	for _, tl := range titleLevels {
+		if tl.pattern.MatchString(titleLower) {
+			result.Level = tl.level
+			break
+		}
+	}
+
+	text := strings.ToLower(description)
+	for _, m := range yearsWithExperience.FindAllStringSubmatch(text, -1) {
+		minYears, _ := strconv.Atoi(m[1])
+		maxYears, _ := strconv.Atoi(m[2])
+		if minYears > result.MinYears && minYears <= 30 {
+			result.MinYears, result.MaxYears = minYears, 0
+			if maxYears > minYears {
+				result.MaxYears = maxYears
+			}
+		}
+	}
+	for _, m := range yearsMinimum.FindAllStringSubmatch(text, -1) {
+		if minYears, _ := strconv.Atoi(m[1]); minYears > result.MinYears && minYears <= 30 {
+			result.MinYears, result.MaxYears = minYears, 0
+		}
+	}
+
+	return result
+}
+
+// userLevel is the level the user is aiming for: the explicit seniority
+// target, or one derived from their years of experience
+func userLevel(target string, years int) int {
+	if level, ok := seniorityTargets[target]; ok {
+		return level
+	}
+
+	switch {
+	case years <= 0:
+		return 0
+	case years < 2:
+		return levelEntry
+	case years < 5:
+		return levelMid
+	case years < 10:
+		return levelSenior
+	default:
+		return levelManager
+	}
+}
+
+// calculateExperienceScore returns how well the job's seniority fits the
+// user, as a fraction of the experience weight. Roles asking for more years
+// or a higher level than the user has are penalized, and so are roles well
+// below them.
+func (s *RealScraper) calculateExperienceScore(job *models.Job, profile *models.ScoringProfile, user *models.UserProfile) (float64, string) {
+	seniority := ExtractSeniority(job.Title, job.Description)
+	years := user.YearsExperience
+	level := userLevel(profile.SeniorityTarget, years)
+
+	if years <= 0 && level == 0 {
+		return 0.5, "set your years of experience on your Profile to score seniority fit"
+	}
+
+	var fits []float64
+	var reasons []string
+
+	if seniority.MinYears > 0 && years > 0 {
+		fit, reason := yearsFit(seniority, years)
+		fits = append(fits, fit)
+		reasons = append(reasons, reason)
+	}
+
+	if seniority.Level > 0 && level > 0 {
+		fit, reason := levelFit(seniority.Level, level)
+		fits = append(fits, fit)
+		reasons = append(reasons, reason)
+	}
+
+	if len(fits) == 0 {
+		return 0.5, "no seniority requirement stated"
+	}
+
+	total := 0.0
+	for _, fit := range fits {
+		total += fit
+	}
+	return total / float64(len(fits)), strings.Join(reasons, "; ")
+}
+
+// yearsFit compares the years a job asks for with the user's. Each missing
+// year costs more than each surplus one: under-qualified candidates rarely get
+// through screening, over-qualified ones just risk a poor match.
+func yearsFit(seniority JobSeniority, years int) (float64, string) {
+	required := fmt.Sprintf("%d+ years", seniority.MinYears)
+	upper := seniority.MinYears + 4
+	if seniority.MaxYears > 0 {
+		required = fmt.Sprintf("%d-%d years", seniority.MinYears, seniority.MaxYears)
+		upper = seniority.MaxYears
+	}
+
+	switch {
+	case years < seniority.MinYears:
+		short := seniority.MinYears - years
+		return max(0, 1-0.35*float64(short)), fmt.Sprintf("requires %s, you have %d (%d short)", required, years, short)
+	case years > upper:
+		over := years - upper
+		return max(0.3, 1-0.1*float64(over)), fmt.Sprintf("requires %s, you have %d (over-qualified)", required, years)
+	default:
+		return 1, fmt.Sprintf("requires %s, you have %d", required, years)
+	}
+}
+
+func levelFit(jobLevel, level int) (float64, string) {
+	reason := fmt.Sprintf("%s-level title, you are %s level", levelNames[jobLevel], levelNames[level])
+
+	switch diff := jobLevel - level; {
+	case diff == 0:
+		return 1, reason
+	case diff == 1:
+		return 0.4, reason + " (a step up)"
+	case diff == -1:
+		return 0.6, reason + " (a step down)"
+	case diff > 1:
+		return 0, reason + " (well above)"
+	default:
+		return 0.2, reason + " (well below)"
+	}
+}
@@ -0,0 +1,57 @@
+package scraper
+
+import "testing"
+
+func TestExtractSeniorityLevel(t *testing.T) {
+	tests := []struct {
+		title string
+		want  int
+	}{
+		{"Senior Security Manager", levelManager},
+		{"Head of Cyber Security", levelManager},
+		{"Senior SOC Analyst", levelSenior},
+		{"Sr. Network Engineer", levelSenior},
+		{"Lead Software Engineer", levelSenior},
+		{"Staff Security Engineer", levelSenior},
+		{"Infrastructure Team Lead", levelSenior},
+		{"Security Engineer III", levelSenior},
+		{"Lead Generation Executive", 0},
+		{"Staff Accountant", 0},
+		{"Software Engineer II", levelMid},
+		{"Software Engineer II - Nairobi", levelMid},
+		{"Mid-level Developer", levelMid},
+		{"Associate Software Engineer", levelEntry},
+		{"Associate Cloud Security Analyst", levelEntry},
+		{"Sales Associate", 0},
+		{"Network Engineer I", levelEntry},
+		{"Analyst I (Contract)", levelEntry},
+		{"I.T. Support Officer", 0},
+		{"IT Officer", 0},
+		{"Graduate Trainee, IT", levelEntry},
+		{"Security Engineer", 0},
+	}
+	for _, tt := range tests {
+		if got := ExtractSeniority(tt.title, "").Level; got != tt.want {
+			t.Errorf("ExtractSeniority(%q).Level = %s, want %s", tt.title, levelNames[got], levelNames[tt.want])
+		}
+	}
+}
+
+func TestExtractSeniorityYears(t *testing.T) {
+	tests := []struct {
+		description        string
+		minYears, maxYears int
+	}{
+		{"You have 3+ years of experience in a SOC.", 3, 0},
+		{"3-5 years' hands-on experience with SIEM tools", 3, 5},
+		{"Minimum of 4 years in networking; 2 years of experience with Python", 4, 0},
+		{"Founded 50 years ago, we value experience", 0, 0},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		got := ExtractSeniority("Engineer", tt.description)
+		if got.MinYears != tt.minYears || got.MaxYears != tt.maxYears {
+			t.Errorf("ExtractSeniority(%q) years = %d-%d, want %d-%d", tt.description, got.MinYears, got.MaxYears, tt.minYears, tt.maxYears)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/C9b3rD3vi1/jobhunter-tool/embeddings"
+	"github.com/C9b3rD3vi1/jobhunter-tool/models"
+	"github.com/C9b3rD3vi1/jobhunter-tool/relevance"
+)
+
+// minSimilarity leaves out jobs that only share a word or two
+const minSimilarity = 0.15
+
+// maxSimilarCandidates caps how many stored jobs a job is compared with
+const maxSimilarCandidates = 500
+
+// How much each signal contributes to similarity, out of 1
+const (
+	similarSkillsWeight      = 0.35
+	similarTitleWeight       = 0.25
+	similarDescriptionWeight = 0.20
+	similarCompanyWeight     = 0.10
+	similarSalaryWeight      = 0.10
+)
+
+// SimilarJob is a job resembling another, with what they have in common
+type SimilarJob struct {
+	Job        models.Job `json:"job"`
+	Similarity float64    `json:"similarity"` // 0 to 1
+	Reasons    []string   `json:"reasons"`
+}
+
+// SimilarJobs finds the stored jobs most like the given one by shared skills
+// and tech, title words, description embeddings, company and salary band.
+// Only the best-scoring maxSimilarCandidates jobs are compared, leaving out
+// dropped and dismissed ones. Descriptions are compared using the vectors
+// stored when jobs are saved or re-scored; the provider is never called.
+func (s *RealScraper) SimilarJobs(ctx context.Context, job *models.Job, limit int) ([]SimilarJob, error) {
+	stored, err := s.db.GetJobs(maxSimilarCandidates, 0)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []models.Job{*job}
+	for _, candidate := range stored {
+		if candidate.ID != job.ID {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	vectors, err := s.storedJobVectors(candidates)
+	if err != nil {
+		// Similarity still works from the other signals
+		log.Printf("⚠️ Error loading job embeddings: %v", err)
+		vectors = make([][]float32, len(candidates))
+	}
+
+	target := newSimilarityProfile(s, job)
+	target.vector = vectors[0]
+
+	var similar []SimilarJob
+	for i := 1; i < len(candidates); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		other := newSimilarityProfile(s, &candidates[i])
+		other.vector = vectors[i]
+		if similarity, reasons := target.compare(other); similarity >= minSimilarity {
+			similar = append(similar, SimilarJob{Job: candidates[i], Similarity: similarity, Reasons: reasons})
+		}
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Similarity > similar[j].Similarity
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+// similarityProfile is what jobs are compared on
+type similarityProfile struct {
+	skills     map[string]string // lowercased skill and tech names to display names
+	titleTerms map[string]bool
+	company    string
+	salary     int
+	vector     []float32
+}
+
+func newSimilarityProfile(s *RealScraper, job *models.Job) similarityProfile {
+	profile := similarityProfile{
+		skills:     map[string]string{},
+		titleTerms: map[string]bool{},
+		company:    strings.ToLower(strings.TrimSpace(job.Company)),
+		salary:     statedSalaryAmount(job.SalaryRange),
+	}
+	if profile.salary == 0 {
+		if salary := s.ExtractSalary(job.Description); salary != "Negotiable" {
+			profile.salary = statedSalaryAmount(salary)
+		}
+	}
+
+	for _, data := range [][]byte{job.Skills, job.TechStack} {
+		var names []string
+		json.Unmarshal(data, &names)
+		for _, name := range names {
+			profile.skills[strings.ToLower(name)] = name
+		}
+	}
+	for _, term := range relevance.Tokenize(job.Title) {
+		profile.titleTerms[term] = true
+	}
+	return profile
+}
+
+func (p similarityProfile) compare(other similarityProfile) (float64, []string) {
+	var similarity float64
+	var reasons []string
+
+	var shared []string
+	for key, name := range p.skills {
+		if _, ok := other.skills[key]; ok {
+			shared = append(shared, name)
+		}
+	}
+	if len(shared) > 0 {
+		sort.Strings(shared)
+		similarity += similarSkillsWeight * jaccard(len(shared), len(p.skills), len(other.skills))
+		reasons = append(reasons, fmt.Sprintf("shares %s", strings.Join(shared, ", ")))
+	}
+
+	sharedTerms := 0
+	for term := range p.titleTerms {
+		if other.titleTerms[term] {
+			sharedTerms++
+		}
+	}
+	if sharedTerms > 0 {
+		similarity += similarTitleWeight * jaccard(sharedTerms, len(p.titleTerms), len(other.titleTerms))
+		reasons = append(reasons, "similar title")
+	}
+
+	if p.vector != nil && other.vector != nil {
+		if cosine := embeddings.Cosine(p.vector, other.vector); cosine > 0 {
+			similarity += similarDescriptionWeight * cosine
+			if cosine >= 0.5 {
+				reasons = append(reasons, "similar description")
+			}
+		}
+	}
+
+	if p.company != "" && p.company == other.company {
+		similarity += similarCompanyWeight
+		reasons = append(reasons, "same company")
+	}
+
+	// Within 25% of each other
+	if p.salary > 0 && other.salary > 0 {
+		low, high := min(p.salary, other.salary), max(p.salary, other.salary)
+		if float64(low) >= 0.75*float64(high) {
+			similarity += similarSalaryWeight
+			reasons = append(reasons, "same salary band")
+		}
+	}
+
+	return similarity, reasons
+}
+
+// jaccard is the size of the intersection over the size of the union
+func jaccard(shared, a, b int) float64 {
+	union := a + b - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,129 @@
+package scraper
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// JobPostingData holds the subset of a schema.org JobPosting (JSON-LD) that
+// job boards embed in their detail pages.
+type JobPostingData struct {
+	Title           string
+	DatePosted      string
+	ValidThrough    string
+	EmploymentTypes []string
+	JobLocationType string
+	Locality        string
+	Region          string
+	Country         string
+}
+
+// Location joins the posting's address parts into a "City, Region, Country" string
+func (p *JobPostingData) Location() string {
+	var parts []string
+	for _, part := range []string{p.Locality, p.Region, p.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseJobPostingJSONLD extracts the first JobPosting object from the
+// contents of a <script type="application/ld+json"> tag. Pages may embed a
+// single object, an array of objects, or an @graph wrapper.
+func parseJobPostingJSONLD(raw string) *JobPostingData {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &doc); err != nil {
+		return nil
+	}
+	return findJobPosting(doc)
+}
+
+func findJobPosting(node interface{}) *JobPostingData {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if posting := findJobPosting(item); posting != nil {
+				return posting
+			}
+		}
+	case map[string]interface{}:
+		if hasSchemaType(v["@type"], "JobPosting") {
+			posting := &JobPostingData{
+				Title:           jsonString(v["title"]),
+				DatePosted:      jsonString(v["datePosted"]),
+				ValidThrough:    jsonString(v["validThrough"]),
+				EmploymentTypes: jsonStrings(v["employmentType"]),
+				JobLocationType: jsonString(v["jobLocationType"]),
+			}
+			if address := findAddress(v["jobLocation"]); address != nil {
+				posting.Locality = jsonName(address["addressLocality"])
+				posting.Region = jsonName(address["addressRegion"])
+				posting.Country = jsonName(address["addressCountry"])
+			}
+			return posting
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findJobPosting(graph)
+		}
+	}
+	return nil
+}
+
+// findAddress returns the PostalAddress of the first jobLocation Place
+func findAddress(node interface{}) map[string]interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if address := findAddress(item); address != nil {
+				return address
+			}
+		}
+	case map[string]interface{}:
+		if address, ok := v["address"].(map[string]interface{}); ok {
+			return address
+		}
+	}
+	return nil
+}
+
+func hasSchemaType(value interface{}, want string) bool {
+	for _, t := range jsonStrings(value) {
+		if strings.EqualFold(t, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return ""
+}
+
+// jsonName reads values that may be a plain string or an object with a "name"
+func jsonName(value interface{}) string {
+	if obj, ok := value.(map[string]interface{}); ok {
+		return jsonString(obj["name"])
+	}
+	return jsonString(value)
+}
+
+func jsonStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{strings.TrimSpace(v)}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := jsonString(item); s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
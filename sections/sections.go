@@ -0,0 +1,233 @@
+package sections
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Section kinds a job description is segmented into
+const (
+	Overview         = "overview"
+	Responsibilities = "responsibilities"
+	Requirements     = "requirements"
+	NiceToHave       = "nice_to_have"
+	Benefits         = "benefits"
+	AboutCompany     = "about_company"
+)
+
+// Section is one headed block of a job description
+type Section struct {
+	Kind    string `json:"kind"`
+	Heading string `json:"heading"`
+	Text    string `json:"text"`
+}
+
+// headingPattern maps heading text to a section kind. Checked in order, so
+// "Preferred Qualifications" is caught as nice-to-have before the
+// requirements pattern sees "qualifications".
+type headingPattern struct {
+	re   *regexp.Regexp
+	kind string
+}
+
+var (
+	headingPatterns = []headingPattern{
+		{regexp.MustCompile(`nice[- ]to[- ]have|good[- ]to[- ]have|preferred|desirable|bonus|added advantage|\bplus\b`), NiceToHave},
+		{regexp.MustCompile(`^about (the|this) (role|job|position|opportunity)`), Responsibilities},
+		{regexp.MustCompile(`^about you`), Requirements},
+		{regexp.MustCompile(`about (us|the company|the organi[sz]ation|the employer)|who we are|company (overview|profile)|our company|^about \w+`), AboutCompany},
+		{regexp.MustCompile(`benefits|perks|what we offer|we offer|why join|compensation|remuneration`), Benefits},
+		{regexp.MustCompile(`responsibilit|duties|what you('|’)?ll do|what you will do|the role|your role|role (summary|description|purpose)|job purpose|day[- ]to[- ]day|key tasks|accountabilit`), Responsibilities},
+		{regexp.MustCompile(`requirement|qualification|must[- ]have|what you('|’)?ll need|what you need|what we('|’)?re looking for|who you are|skills (and|&) experience|experience (and|&) skills|competenc|education|academic|job specifications?|person specification|^(key |technical )?(skills|experience)$`), Requirements},
+	}
+
+	// Bullets inside a requirements list that are really optional
+	optionalLine = regexp.MustCompile(`added advantage|an advantage|is a plus|nice[- ]to[- ]have|preferred|desirable|bonus`)
+
+	headingMarkup = regexp.MustCompile(`^[\s*#]+`)
+	listItem      = regexp.MustCompile(`^([-*•·●▪◦–]|\d+[.)])\s`)
+
+	weights = map[string]float64{
+		Requirements:     1.0,
+		Overview:         0.8,
+		Responsibilities: 0.8,
+		NiceToHave:       0.4,
+		AboutCompany:     0.2,
+		Benefits:         0.1,
+	}
+)
+
+// maxHeadingLength keeps sentences that merely mention "requirements" from
+// being treated as headings
+const maxHeadingLength = 60
+
+// Parse segments a job description into sections by recognizing heading
+// lines ("Key Responsibilities", "Requirements:", "Nice to have"). Text before
+// the first heading becomes an overview section. Requirement bullets that
+// describe themselves as optional ("... is an added advantage") are moved to
+// the nice-to-have section.
+func Parse(description string) []Section {
+	var result []Section
+	current := Section{Kind: Overview}
+	var body []string
+	var optional []string
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(body, "\n"))
+		if text != "" || current.Heading != "" {
+			current.Text = text
+			result = append(result, current)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(description, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if kind, heading, rest, ok := detectHeading(trimmed); ok {
+			flush()
+			current = Section{Kind: kind, Heading: heading}
+			if rest != "" {
+				body = append(body, rest)
+			}
+			continue
+		}
+
+		if current.Kind == Requirements && optionalLine.MatchString(strings.ToLower(trimmed)) {
+			optional = append(optional, trimmed)
+			continue
+		}
+		body = append(body, trimmed)
+	}
+	flush()
+
+	if len(optional) > 0 {
+		result = appendToKind(result, NiceToHave, optional)
+	}
+	return result
+}
+
+// Weight returns how much a term found in a section of this kind should
+// count towards matching, relative to a hard requirement.
+func Weight(kind string) float64 {
+	if w, ok := weights[kind]; ok {
+		return w
+	}
+	return weights[Overview]
+}
+
+// TermWeight returns the weight of the most important section mentioning
+// term (see Mentions), or 0 when it is not mentioned at all.
+// Descriptions without any recognizable headings count fully, so unsectioned
+// postings score as they always have.
+func TermWeight(parsed []Section, term string) float64 {
+	_, weight := BestMatch(parsed, term)
+	return weight
+}
+
+// BestMatch is TermWeight that also reports the kind of section the term was
+// found in; the kind is empty when the term is not mentioned.
+func BestMatch(parsed []Section, term string) (string, float64) {
+	term = strings.ToLower(term)
+	if term == "" {
+		return "", 0
+	}
+
+	if len(parsed) == 1 && parsed[0].Kind == Overview {
+		if Mentions(parsed[0].Text, term) {
+			return Overview, 1.0
+		}
+		return "", 0
+	}
+
+	kind, best := "", 0.0
+	for _, section := range parsed {
+		if Weight(section.Kind) > best && Mentions(section.Text, term) {
+			kind, best = section.Kind, Weight(section.Kind)
+		}
+	}
+	return kind, best
+}
+
+// termPatterns caches the compiled pattern for each term passed to Mentions
+var termPatterns sync.Map
+
+// Mentions reports whether text names term as a whole word or phrase,
+// ignoring case, so "go" isn't found in "Google" nor "soc" in "associate".
+// "+" and "#" count as part of a word, keeping "c" apart from "c++" and "c#".
+func Mentions(text, term string) bool {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return false
+	}
+	pattern, ok := termPatterns.Load(term)
+	if !ok {
+		pattern, _ = termPatterns.LoadOrStore(term, regexp.MustCompile(`(?i)(^|[^\w+#])`+regexp.QuoteMeta(term)+`($|[^\w+#])`))
+	}
+	return pattern.(*regexp.Regexp).MatchString(text)
+}
+
+// Text returns the combined text of all sections of the given kind
+func Text(parsed []Section, kind string) string {
+	var parts []string
+	for _, section := range parsed {
+		if section.Kind == kind {
+			parts = append(parts, section.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func detectHeading(line string) (kind, heading, rest string, ok bool) {
+	if listItem.MatchString(line) {
+		return "", "", "", false
+	}
+	candidate := headingMarkup.ReplaceAllString(line, "")
+
+	// Inline headings: "Requirements: Degree in Computer Science..."
+	if idx := strings.Index(candidate, ":"); idx > 0 && idx <= maxHeadingLength {
+		if kind, ok := classifyHeading(candidate[:idx]); ok {
+			return kind, cleanHeading(candidate[:idx]), strings.TrimSpace(candidate[idx+1:]), true
+		}
+	}
+
+	if len(candidate) > maxHeadingLength || strings.HasSuffix(candidate, ".") {
+		return "", "", "", false
+	}
+	if kind, ok := classifyHeading(candidate); ok {
+		return kind, cleanHeading(candidate), "", true
+	}
+	return "", "", "", false
+}
+
+func classifyHeading(text string) (string, bool) {
+	text = strings.ToLower(cleanHeading(text))
+	if text == "" || len(strings.Fields(text)) > 8 {
+		return "", false
+	}
+	for _, pattern := range headingPatterns {
+		if pattern.re.MatchString(text) {
+			return pattern.kind, true
+		}
+	}
+	return "", false
+}
+
+// cleanHeading strips Markdown emphasis and trailing colons from a heading
+func cleanHeading(text string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(text), "*#:-–_"))
+}
+
+func appendToKind(parsed []Section, kind string, lines []string) []Section {
+	for i := range parsed {
+		if parsed[i].Kind == kind {
+			parsed[i].Text = strings.TrimSpace(parsed[i].Text + "\n" + strings.Join(lines, "\n"))
+			return parsed
+		}
+	}
+	return append(parsed, Section{Kind: kind, Text: strings.Join(lines, "\n")})
+}
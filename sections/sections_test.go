@@ -0,0 +1,52 @@
+package sections
+
+import "testing"
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		term string
+		want bool
+	}{
+		{"Experience with Go and Python", "go", true},
+		{"Good communication", "go", false},
+		{"Google Cloud certification", "Go", false},
+		{"Built a SOC from scratch", "soc", true},
+		{"Associate engineer", "SOC", false},
+		{"IDS/IPS tuning", "ips", true},
+		{"Strong relationships", "ips", false},
+		{"Git, Docker", "git", true},
+		{"Digital marketing", "git", false},
+		{"Incident response lead", "incident response", true},
+		{"C++ and C#", "c", false},
+		{"C++ and C#", "c++", true},
+		{"", "go", false},
+		{"go", " ", false},
+	}
+	for _, tt := range tests {
+		if got := Mentions(tt.text, tt.term); got != tt.want {
+			t.Errorf("Mentions(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestBestMatch(t *testing.T) {
+	parsed := Parse("We are a Google partner hiring an associate.\n\nRequirements:\n- Linux administration\n\nNice to have:\n- Go scripting")
+
+	tests := []struct {
+		term   string
+		kind   string
+		weight float64
+	}{
+		{"linux", Requirements, Weight(Requirements)},
+		{"Go", NiceToHave, Weight(NiceToHave)},
+		{"soc", "", 0},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		kind, weight := BestMatch(parsed, tt.term)
+		if kind != tt.kind || weight != tt.weight {
+			t.Errorf("BestMatch(%q) = %q, %v, want %q, %v", tt.term, kind, weight, tt.kind, tt.weight)
+		}
+	}
+}
@@ -96,6 +96,100 @@ function showNotification(message, type = 'info') {
     }, 5000);
 }
 
+// Like or dismiss a job; clicking the active signal again clears it
+function rateJob(jobId, signal, button) {
+    if (button && button.classList.contains('active')) {
+        signal = '';
+    }
+
+    fetch(`/jobs/${jobId}/feedback`, {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ signal: signal })
+    })
+    .then(response => response.json())
+    .then(result => {
+        if (result.status !== 'success') {
+            showNotification(result.error || 'Failed to save feedback', 'error');
+            return;
+        }
+        showNotification(result.message, 'success');
+
+        const card = button && button.closest('.job-card');
+        if (card && signal === 'dismissed') {
+            card.remove();
+            return;
+        }
+        if (button) {
+            button.parentElement.querySelectorAll('.feedback-btn').forEach(b => b.classList.remove('active'));
+            if (signal) {
+                button.classList.add('active');
+            }
+        }
+    })
+    .catch(error => {
+        showNotification('Failed to save feedback: ' + error.message, 'error');
+    });
+}
+
+// Stream a cover letter from the server, calling onToken with each piece of
+// text as it arrives. Resolves with the final letter once it has been saved.
+async function streamCoverLetter(request, onToken) {
+    const response = await fetch('/cover-letter/stream', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json',
+        },
+        body: JSON.stringify(request)
+    });
+
+    if (!response.ok) {
+        const result = await response.json().catch(() => ({}));
+        throw new Error(result.error || `Request failed (${response.status})`);
+    }
+
+    const reader = response.body.getReader();
+    const decoder = new TextDecoder();
+    let buffer = '';
+
+    while (true) {
+        const { value, done } = await reader.read();
+        if (done) {
+            break;
+        }
+        buffer += decoder.decode(value, { stream: true });
+
+        let boundary;
+        while ((boundary = buffer.indexOf('\n\n')) !== -1) {
+            const message = buffer.slice(0, boundary);
+            buffer = buffer.slice(boundary + 2);
+
+            let event = 'message';
+            let data = '';
+            message.split('\n').forEach(line => {
+                if (line.startsWith('event: ')) {
+                    event = line.slice(7);
+                } else if (line.startsWith('data: ')) {
+                    data += line.slice(6);
+                }
+            });
+
+            const payload = JSON.parse(data || '{}');
+            if (event === 'token') {
+                onToken(payload.text);
+            } else if (event === 'error') {
+                throw new Error(payload.error);
+            } else if (event === 'done') {
+                return payload;
+            }
+        }
+    }
+
+    throw new Error('The cover letter stream ended unexpectedly');
+}
+
 // Initialize when page loads
 document.addEventListener('DOMContentLoaded', function() {
     // Add loading states to all forms
@@ -141,4 +235,5 @@ document.addEventListener('DOMContentLoaded', function() {
 // Export functions for global access
 window.toggleMenu = toggleMenu;
 window.truncate = truncate;
-window.showNotification = showNotification;
\ No newline at end of file
+window.showNotification = showNotification;
+window.rateJob = rateJob;
\ No newline at end of file